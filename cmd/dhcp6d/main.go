@@ -71,14 +71,14 @@ func handle(ip net.IP, w dhcp6server.ResponseSender, r *dhcp6server.Request) err
 	}
 
 	// Make sure client sent a client ID.
-	duid, err := r.Options.GetOne(dhcp6.OptionClientID)
+	duid, err := dhcp6opts.GetClientID(r.Options)
 	if err != nil {
 		return nil
 	}
 
 	// Log information about the incoming request.
-	log.Printf("[%s] id: %s, type: %d, len: %d, tx: %s",
-		hex.EncodeToString(duid),
+	log.Printf("[%v] id: %s, type: %d, len: %d, tx: %s",
+		duid,
 		r.RemoteAddr,
 		r.MessageType,
 		r.Length,
@@ -122,7 +122,7 @@ func handle(ip net.IP, w dhcp6server.ResponseSender, r *dhcp6server.Request) err
 
 	// IANA may already have an IAAddr if an address was already assigned.
 	// If not, assign a new one.
-	iaaddrs, err := dhcp6opts.GetIAAddr(ia.Options)
+	iaaddrs, err := ia.IAAddrs()
 	switch err {
 	case dhcp6.ErrOptionNotPresent:
 		// Client did not indicate a previous address, and is soliciting.
@@ -142,21 +142,24 @@ func handle(ip net.IP, w dhcp6server.ResponseSender, r *dhcp6server.Request) err
 
 	// Confirm or renew an existing IPv6 address
 
-	// Must have an IAAddr, but we ignore if more than one is present
+	// Must have at least one IAAddr
 	if len(iaaddrs) == 0 {
 		return nil
 	}
-	iaa := iaaddrs[0]
 
-	log.Printf("\t\tIAAddr: %s (%s, %s), opts: %v",
-		iaa.IP,
-		iaa.PreferredLifetime,
-		iaa.ValidLifetime,
-		iaa.Options,
-	)
+	for _, iaa := range iaaddrs {
+		log.Printf("\t\tIAAddr: %s (%s, %s), opts: %v",
+			iaa.IP,
+			iaa.PreferredLifetime,
+			iaa.ValidLifetime,
+			iaa.Options,
+		)
+	}
 
-	// Add IAAddr inside IANA, add IANA to options
-	_ = ia.Options.Add(dhcp6.OptionIAAddr, iaa)
+	// Confirm all IAAddrs inside IANA, add IANA to options
+	if err := ia.SetAddresses(iaaddrs...); err != nil {
+		return err
+	}
 	_ = w.Options().Add(dhcp6.OptionIANA, ia)
 
 	// Send reply to client
@@ -169,13 +172,19 @@ func handle(ip net.IP, w dhcp6server.ResponseSender, r *dhcp6server.Request) err
 func newIAAddr(ia *dhcp6opts.IANA, ip net.IP, w dhcp6server.ResponseSender, r *dhcp6server.Request) error {
 	// Send IPv6 address with 60 second preferred lifetime,
 	// 90 second valid lifetime, no extra options
-	iaaddr, err := dhcp6opts.NewIAAddr(ip, 60*time.Second, 90*time.Second, nil)
+	preferred := 60 * time.Second
+	iaaddr, err := dhcp6opts.NewIAAddr(ip, preferred, 90*time.Second, nil)
 	if err != nil {
 		return err
 	}
 
+	// Instruct client to renew and rebind at the recommended times
+	ia.T1, ia.T2 = dhcp6opts.RecommendedT1T2(preferred)
+
 	// Add IAAddr inside IANA, add IANA to options
-	_ = ia.Options.Add(dhcp6.OptionIAAddr, iaaddr)
+	if err := ia.SetAddresses(iaaddr); err != nil {
+		return err
+	}
 	_ = w.Options().Add(dhcp6.OptionIANA, ia)
 
 	// Advertise address to soliciting clients
@@ -6,7 +6,7 @@ package dhcp6
 type MessageType uint8
 
 // MessageType constants which indicate the message types described in
-// RFCs 3315, 5007, 5460, 6977, and 7341.
+// RFCs 3315, 5007, 5460, 6977, 7341, and 9686.
 //
 // These message types are taken from IANA's DHCPv6 parameters registry:
 // http://www.iana.org/assignments/dhcpv6-parameters/dhcpv6-parameters.xhtml.
@@ -41,6 +41,10 @@ const (
 	// RFC 7341
 	MessageTypeDHCPv4Query    MessageType = 20
 	MessageTypeDHCPv4Response MessageType = 21
+
+	// RFC 9686
+	MessageTypeAddrRegInform MessageType = 36
+	MessageTypeAddrRegReply  MessageType = 37
 )
 
 // Status represesents a DHCP status code, as defined in RFC 3315,
@@ -81,7 +85,7 @@ const (
 type OptionCode uint16
 
 // OptionCode constants which indicate the option codes described in
-// RFC 3315, RFC 3633, and RFC 5970.
+// RFCs 3315, 3633, 3646, 4075, 4242, 4649, 5908, 5970, 6422, and 9686.
 //
 // These option codes are taken from IANA's DHCPv6 parameters registry:
 // http://www.iana.org/assignments/dhcpv6-parameters/dhcpv6-parameters.xhtml.
@@ -110,19 +114,35 @@ const (
 
 	// RFC 3646
 	OptionDNSServers OptionCode = 23
+	OptionDomainList OptionCode = 24
 
 	// RFC 3633
 	OptionIAPD     OptionCode = 25
 	OptionIAPrefix OptionCode = 26
 
+	// RFC 4075
+	OptionSNTPServers OptionCode = 31
+
+	// RFC 4242
+	OptionInformationRefreshTime OptionCode = 32
+
 	// RFC 4649
 	OptionRemoteIdentifier OptionCode = 37
 
+	// RFC 5908
+	OptionNTPServer OptionCode = 56
+
 	// RFC 5970
 	OptionBootFileURL    OptionCode = 59
 	OptionBootFileParam  OptionCode = 60
 	OptionClientArchType OptionCode = 61
 	OptionNII            OptionCode = 62
 
+	// RFC 6422
+	OptionRSOO OptionCode = 66
+
+	// RFC 9686
+	OptionAddrRegEnable OptionCode = 148
+
 	// BUG(mdlayher): add additional option code types defined by IANA
 )
@@ -21,7 +21,27 @@ var (
 	// and transaction ID.
 	ErrInvalidPacket = errors.New("not enough bytes for valid packet")
 
+	// ErrInvalidTransactionID is returned by Packet.SetTransactionID when a
+	// transaction ID is not exactly 3 bytes long.
+	ErrInvalidTransactionID = errors.New("transaction ID must be exactly 3 bytes")
+
 	// ErrOptionNotPresent is returned when a requested opcode is not in
 	// the packet.
 	ErrOptionNotPresent = errors.New("option code not present in packet")
+
+	// ErrOptionTooLarge is returned by Options.MarshalBinary when an option
+	// value is too large for its length to be encoded in 16 bits.
+	ErrOptionTooLarge = errors.New("option value too large")
+
+	// ErrNoDecoder is returned by Options.Decode when no decoder has been
+	// registered for an option code using RegisterDecoder.
+	ErrNoDecoder = errors.New("no decoder registered for option code")
+
+	// ErrInvalidUDP6Addr is returned by MarshalUDP6 when a source or
+	// destination address does not contain a valid IPv6 address and port.
+	ErrInvalidUDP6Addr = errors.New("invalid IPv6 UDP address")
+
+	// ErrPayloadTooLarge is returned by MarshalUDP6 when a payload is too
+	// large to fit in a single UDP datagram.
+	ErrPayloadTooLarge = errors.New("payload too large for UDP datagram")
 )
@@ -0,0 +1,95 @@
+package dhcp6opts
+
+import (
+	"time"
+
+	"github.com/mdlayher/dhcp6"
+)
+
+// An AdvertiseSummary is a comparable summary of the configuration offered
+// by a server in an Advertise message.  A client may use AdvertiseSummary
+// values to implement a policy for selecting a server, as described in
+// RFC 3315, Section 17.1.3.
+type AdvertiseSummary struct {
+	// Preference is the server's preference value.  If the Advertise
+	// does not contain a Preference option, Preference is 0.
+	Preference Preference
+
+	// Addresses is the number of addresses offered in IANA and IATA
+	// options.
+	Addresses int
+
+	// Prefixes is the number of prefixes offered in IAPD options.
+	Prefixes int
+
+	// MaxValidLifetime is the longest valid lifetime of any offered
+	// address or prefix.
+	MaxValidLifetime time.Duration
+
+	// Missing contains option codes which were requested, but are not
+	// present in the Advertise, sorted in ascending order.  Each code
+	// appears only once.  If all requested options are present, Missing
+	// is empty.
+	Missing []dhcp6.OptionCode
+}
+
+// SummarizeAdvertise produces an AdvertiseSummary from an Advertise message,
+// checking for the presence of each option code in requested.
+//
+// If p is not an Advertise message, ErrNotAdvertise is returned.  If any
+// option used to produce the summary is malformed, its error is returned.
+func SummarizeAdvertise(p *dhcp6.Packet, requested []dhcp6.OptionCode) (*AdvertiseSummary, error) {
+	if p.MessageType != dhcp6.MessageTypeAdvertise {
+		return nil, ErrNotAdvertise
+	}
+
+	s := new(AdvertiseSummary)
+
+	pref, err := GetPreference(p.Options)
+	switch err {
+	case nil:
+		s.Preference = pref
+	case dhcp6.ErrOptionNotPresent:
+	default:
+		return nil, err
+	}
+
+	iaas, err := GetAllIAAddrs(p.Options)
+	if err != nil {
+		return nil, err
+	}
+	s.Addresses = len(iaas)
+	for _, iaa := range iaas {
+		if iaa.ValidLifetime > s.MaxValidLifetime {
+			s.MaxValidLifetime = iaa.ValidLifetime
+		}
+	}
+
+	iaps, err := GetAllIAPrefixes(p.Options)
+	if err != nil {
+		return nil, err
+	}
+	s.Prefixes = len(iaps)
+	for _, iap := range iaps {
+		if iap.ValidLifetime > s.MaxValidLifetime {
+			s.MaxValidLifetime = iap.ValidLifetime
+		}
+	}
+
+	// Each missing option code is reported once, even if it was requested
+	// more than once.
+	seen := make(map[dhcp6.OptionCode]struct{}, len(requested))
+	for _, code := range requested {
+		if _, ok := seen[code]; ok {
+			continue
+		}
+		seen[code] = struct{}{}
+
+		if _, ok := p.Options[code]; !ok {
+			s.Missing = append(s.Missing, code)
+		}
+	}
+	sortOptionCodes(s.Missing)
+
+	return s, nil
+}
@@ -0,0 +1,149 @@
+package dhcp6opts
+
+import (
+	"io"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mdlayher/dhcp6"
+)
+
+// TestSummarizeAdvertise verifies that SummarizeAdvertise produces a
+// comparable summary for Advertise messages offering different
+// configurations.
+func TestSummarizeAdvertise(t *testing.T) {
+	// SNTP servers are requested twice, but must be reported as missing
+	// only once.
+	requested := []dhcp6.OptionCode{
+		dhcp6.OptionSNTPServers,
+		dhcp6.OptionDNSServers,
+		dhcp6.OptionSNTPServers,
+	}
+
+	var tests = []struct {
+		desc string
+		p    *dhcp6.Packet
+		s    *AdvertiseSummary
+		err  error
+	}{
+		{
+			desc: "Reply message",
+			p: &dhcp6.Packet{
+				MessageType: dhcp6.MessageTypeReply,
+			},
+			err: ErrNotAdvertise,
+		},
+		{
+			desc: "malformed Preference option",
+			p: &dhcp6.Packet{
+				MessageType: dhcp6.MessageTypeAdvertise,
+				Options: dhcp6.Options{
+					dhcp6.OptionPreference: [][]byte{{1, 2}},
+				},
+			},
+			err: io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "no preference, one address, one prefix, DNS servers",
+			p: &dhcp6.Packet{
+				MessageType: dhcp6.MessageTypeAdvertise,
+				Options: testSummaryOptions(t, nil,
+					[]time.Duration{1 * time.Hour},
+					[]time.Duration{2 * time.Hour},
+					dhcp6.OptionDNSServers,
+				),
+			},
+			s: &AdvertiseSummary{
+				Addresses:        1,
+				Prefixes:         1,
+				MaxValidLifetime: 2 * time.Hour,
+				Missing:          []dhcp6.OptionCode{dhcp6.OptionSNTPServers},
+			},
+		},
+		{
+			desc: "preference 255, two addresses, all requested options",
+			p: &dhcp6.Packet{
+				MessageType: dhcp6.MessageTypeAdvertise,
+				Options: testSummaryOptions(t, []byte{255},
+					[]time.Duration{30 * time.Minute, 4 * time.Hour},
+					nil,
+					dhcp6.OptionDNSServers, dhcp6.OptionSNTPServers,
+				),
+			},
+			s: &AdvertiseSummary{
+				Preference:       255,
+				Addresses:        2,
+				MaxValidLifetime: 4 * time.Hour,
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		s, err := SummarizeAdvertise(tt.p, requested)
+		if want, got := tt.err, err; want != got {
+			t.Errorf("[%02d] test %q, unexpected error for SummarizeAdvertise: %v != %v",
+				i, tt.desc, want, got)
+			continue
+		}
+
+		if want, got := tt.s, s; !reflect.DeepEqual(want, got) {
+			t.Errorf("[%02d] test %q, unexpected AdvertiseSummary:\n- want: %+v\n-  got: %+v",
+				i, tt.desc, want, got)
+		}
+	}
+}
+
+// testSummaryOptions builds Advertise options for TestSummarizeAdvertise,
+// with one IANA address and one IAPD prefix per valid lifetime, and an empty
+// value for each additional option code.
+func testSummaryOptions(t *testing.T, pref []byte, addrs, prefixes []time.Duration, codes ...dhcp6.OptionCode) dhcp6.Options {
+	t.Helper()
+
+	o := make(dhcp6.Options)
+	if pref != nil {
+		o.AddRaw(dhcp6.OptionPreference, pref)
+	}
+
+	iaOpts := make(dhcp6.Options)
+	for i, valid := range addrs {
+		iaa, err := NewIAAddr(net.ParseIP("2001:db8::1").To16(), valid/2, valid, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		iaa.IP[15] = byte(i + 1)
+
+		if err := iaOpts.Add(dhcp6.OptionIAAddr, iaa); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(addrs) > 0 {
+		if err := o.Add(dhcp6.OptionIANA, NewIANA([4]byte{0, 0, 0, 1}, 0, 0, iaOpts)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pdOpts := make(dhcp6.Options)
+	for _, valid := range prefixes {
+		iap, err := NewIAPrefix(valid/2, valid, 56, net.ParseIP("2001:db8:1::"), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := pdOpts.Add(dhcp6.OptionIAPrefix, iap); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(prefixes) > 0 {
+		if err := o.Add(dhcp6.OptionIAPD, NewIAPD([4]byte{0, 0, 0, 2}, 0, 0, pdOpts)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, code := range codes {
+		o.AddRaw(code, nil)
+	}
+
+	return o
+}
@@ -1,11 +1,26 @@
 package dhcp6opts
 
 import (
+	"crypto/hmac"
+	"crypto/md5"
+	"encoding/binary"
 	"io"
 
+	"github.com/mdlayher/dhcp6"
 	"github.com/mdlayher/dhcp6/internal/buffer"
 )
 
+// Authentication protocol, algorithm, and replay detection method values,
+// as described in RFC 3315, Section 21.
+const (
+	AuthProtocolDelayed        byte = 2
+	AuthProtocolReconfigureKey byte = 3
+
+	AuthAlgorithmHMACMD5 byte = 1
+
+	AuthRDMMonotonicCounter byte = 0
+)
+
 // The Authentication option carries authentication information to
 // authenticate the identity and contents of DHCP messages. The use of
 // the Authentication option is described in section 21.
@@ -61,3 +76,119 @@ func (a *Authentication) UnmarshalBinary(p []byte) error {
 	a.AuthenticationInformation = b.Remaining()
 	return nil
 }
+
+// AuthSign adds an Authentication option based on a to p's options, replacing
+// any existing Authentication option, and signs p using key, as described in
+// RFC 3315, Section 21.
+//
+// a.AuthenticationInformation holds any data which precedes the HMAC in the
+// authentication information field, such as the DHCP realm and key ID for
+// the delayed authentication protocol, or the type value 2 for the
+// reconfigure key authentication protocol.  The HMAC is appended to this
+// data, and is computed over p with the HMAC set to zero.
+//
+// Only AuthAlgorithmHMACMD5 is supported.  Other algorithms return
+// ErrUnsupportedAuthAlgorithm.
+func AuthSign(p *dhcp6.Packet, a *Authentication, key []byte) error {
+	if a.Algorithm != AuthAlgorithmHMACMD5 {
+		return ErrUnsupportedAuthAlgorithm
+	}
+
+	// Build the option with a zeroed HMAC
+	auth := *a
+	n := len(a.AuthenticationInformation)
+	auth.AuthenticationInformation = make([]byte, n+md5.Size)
+	copy(auth.AuthenticationInformation, a.AuthenticationInformation)
+
+	if p.Options == nil {
+		p.Options = make(dhcp6.Options)
+	}
+	delete(p.Options, dhcp6.OptionAuth)
+	if err := p.Options.Add(dhcp6.OptionAuth, &auth); err != nil {
+		return err
+	}
+
+	b, err := p.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	// Replace the option with one containing the computed HMAC
+	copy(auth.AuthenticationInformation[n:], authHMAC(key, b))
+
+	delete(p.Options, dhcp6.OptionAuth)
+	return p.Options.Add(dhcp6.OptionAuth, &auth)
+}
+
+// AuthVerify verifies the HMAC in the Authentication option of the raw DHCP
+// message b using key, as described in RFC 3315, Section 21.  The HMAC is
+// the final 16 bytes of the option's authentication information field.
+//
+// A message's raw bytes are verified, rather than a Packet, because the HMAC
+// covers options in the order the sender encoded them.
+//
+// If b contains no Authentication option, dhcp6.ErrOptionNotPresent is
+// returned.  If the HMAC does not match, ErrAuthFailed is returned.
+func AuthVerify(b []byte, key []byte) error {
+	if len(b) < 4 {
+		return dhcp6.ErrInvalidPacket
+	}
+
+	// Copy the message so the HMAC can be zeroed
+	msg := make([]byte, len(b))
+	copy(msg, b)
+
+	// Find the Authentication option, skipping the message type and
+	// transaction ID
+	for i := 4; i < len(msg); {
+		if len(msg)-i < 4 {
+			return dhcp6.ErrInvalidOptions
+		}
+
+		code := dhcp6.OptionCode(binary.BigEndian.Uint16(msg[i : i+2]))
+		start := i + 4
+		end := start + int(binary.BigEndian.Uint16(msg[i+2:i+4]))
+		if end > len(msg) {
+			return dhcp6.ErrInvalidOptions
+		}
+		i = end
+
+		if code != dhcp6.OptionAuth {
+			continue
+		}
+
+		a := new(Authentication)
+		if err := a.UnmarshalBinary(msg[start:end]); err != nil {
+			return err
+		}
+		if a.Algorithm != AuthAlgorithmHMACMD5 {
+			return ErrUnsupportedAuthAlgorithm
+		}
+		if len(a.AuthenticationInformation) < md5.Size {
+			return io.ErrUnexpectedEOF
+		}
+
+		// The HMAC is computed with the HMAC field set to zero
+		field := msg[end-md5.Size : end]
+		mac := make([]byte, md5.Size)
+		copy(mac, field)
+		for j := range field {
+			field[j] = 0
+		}
+
+		if !hmac.Equal(mac, authHMAC(key, msg)) {
+			return ErrAuthFailed
+		}
+
+		return nil
+	}
+
+	return dhcp6.ErrOptionNotPresent
+}
+
+// authHMAC computes the HMAC-MD5 of b using key.
+func authHMAC(key []byte, b []byte) []byte {
+	h := hmac.New(md5.New, key)
+	_, _ = h.Write(b)
+	return h.Sum(nil)
+}
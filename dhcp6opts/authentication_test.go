@@ -3,9 +3,12 @@ package dhcp6opts
 import (
 	"bytes"
 	"encoding/binary"
+	"encoding/hex"
 	"io"
 	"reflect"
 	"testing"
+
+	"github.com/mdlayher/dhcp6"
 )
 
 func TestAuthenticationMarshalBinary(t *testing.T) {
@@ -82,3 +85,160 @@ func TestAuthenticationUnmarshalBinary(t *testing.T) {
 		}
 	}
 }
+
+// TestAuthSignVerify verifies that AuthSign produces a known HMAC-MD5 for a
+// fixed key and message, and that AuthVerify accepts the signed message.
+func TestAuthSignVerify(t *testing.T) {
+	key := []byte("secret")
+
+	p := &dhcp6.Packet{
+		MessageType:   dhcp6.MessageTypeSolicit,
+		TransactionID: [3]byte{1, 2, 3},
+		Options: dhcp6.Options{
+			dhcp6.OptionClientID: [][]byte{{0, 1}},
+		},
+	}
+
+	// Reconfigure key authentication, HMAC-MD5 digest type
+	a := &Authentication{
+		Protocol:                  AuthProtocolReconfigureKey,
+		Algorithm:                 AuthAlgorithmHMACMD5,
+		RDM:                       AuthRDMMonotonicCounter,
+		ReplayDetection:           1,
+		AuthenticationInformation: []byte{2},
+	}
+
+	if err := AuthSign(p, a, key); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := GetAuthentication(p.Options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mac, err := hex.DecodeString("676f4753d0817dbbdb31e10c768f5974")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := append([]byte{2}, mac...), got.AuthenticationInformation; !bytes.Equal(want, got) {
+		t.Fatalf("unexpected authentication information:\n- want: %v\n-  got: %v", want, got)
+	}
+
+	b, err := p.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := AuthVerify(b, key); err != nil {
+		t.Fatalf("failed to verify signed message: %v", err)
+	}
+
+	// Signing again replaces the existing option
+	if err := AuthSign(p, a, key); err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 1, len(p.Options[dhcp6.OptionAuth]); want != got {
+		t.Fatalf("unexpected number of Authentication options: %v != %v", want, got)
+	}
+}
+
+// TestAuthVerifyErrors verifies that AuthVerify rejects messages which are
+// malformed, unsigned, or do not match their HMAC.
+func TestAuthVerifyErrors(t *testing.T) {
+	key := []byte("secret")
+
+	sign := func(algorithm byte) []byte {
+		p := &dhcp6.Packet{
+			MessageType:   dhcp6.MessageTypeReply,
+			TransactionID: [3]byte{1, 2, 3},
+		}
+
+		a := &Authentication{
+			Protocol:  AuthProtocolDelayed,
+			Algorithm: AuthAlgorithmHMACMD5,
+		}
+		if err := AuthSign(p, a, key); err != nil {
+			t.Fatal(err)
+		}
+
+		// Change the algorithm after signing, if needed
+		if algorithm != AuthAlgorithmHMACMD5 {
+			got, err := GetAuthentication(p.Options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got.Algorithm = algorithm
+
+			delete(p.Options, dhcp6.OptionAuth)
+			if err := p.Options.Add(dhcp6.OptionAuth, got); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		b, err := p.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+
+	tampered := sign(AuthAlgorithmHMACMD5)
+	tampered[1] = 0xff
+
+	var tests = []struct {
+		desc string
+		b    []byte
+		key  []byte
+		err  error
+	}{
+		{
+			desc: "too short",
+			b:    []byte{1, 2, 3},
+			err:  dhcp6.ErrInvalidPacket,
+		},
+		{
+			desc: "truncated option",
+			b:    []byte{1, 2, 3, 4, 0, 11, 0, 5, 0},
+			err:  dhcp6.ErrInvalidOptions,
+		},
+		{
+			desc: "no Authentication option",
+			b:    []byte{1, 2, 3, 4, 0, 1, 0, 2, 0, 1},
+			err:  dhcp6.ErrOptionNotPresent,
+		},
+		{
+			desc: "unsupported algorithm",
+			b:    sign(2),
+			err:  ErrUnsupportedAuthAlgorithm,
+		},
+		{
+			desc: "wrong key",
+			b:    sign(AuthAlgorithmHMACMD5),
+			key:  []byte("wrong"),
+			err:  ErrAuthFailed,
+		},
+		{
+			desc: "tampered message",
+			b:    tampered,
+			err:  ErrAuthFailed,
+		},
+	}
+
+	for i, tt := range tests {
+		k := tt.key
+		if k == nil {
+			k = key
+		}
+
+		if want, got := tt.err, AuthVerify(tt.b, k); want != got {
+			t.Fatalf("[%02d] test %q, unexpected error for AuthVerify: %v != %v",
+				i, tt.desc, want, got)
+		}
+	}
+
+	// AuthSign rejects unsupported algorithms
+	if want, got := ErrUnsupportedAuthAlgorithm, AuthSign(&dhcp6.Packet{}, &Authentication{}, key); want != got {
+		t.Fatalf("unexpected error for AuthSign: %v != %v", want, got)
+	}
+}
@@ -0,0 +1,52 @@
+package dhcp6opts
+
+import (
+	"sort"
+
+	"github.com/mdlayher/dhcp6"
+)
+
+// OptionsDiff compares the options requested by a client using the Option
+// Request Option in req with the options present in resp, and is intended
+// to help debug mismatches between a request and its response.
+//
+// missing contains option codes which were requested, but are not present in
+// resp.  extra contains option codes which are present in resp, but were not
+// requested.  Options which are not typically requested using the Option
+// Request Option, such as the Client and Server Identifier options, are
+// reported as extra.  Both slices are sorted in ascending order.
+//
+// If req does not contain a valid Option Request Option, no options are
+// considered to be requested.
+func OptionsDiff(req, resp dhcp6.Options) (missing, extra []dhcp6.OptionCode) {
+	requested := make(map[dhcp6.OptionCode]struct{})
+	if oro, err := GetOptionRequest(req); err == nil {
+		for _, code := range oro {
+			requested[code] = struct{}{}
+		}
+	}
+
+	for code := range requested {
+		if _, ok := resp[code]; !ok {
+			missing = append(missing, code)
+		}
+	}
+
+	for code := range resp {
+		if _, ok := requested[code]; !ok {
+			extra = append(extra, code)
+		}
+	}
+
+	sortOptionCodes(missing)
+	sortOptionCodes(extra)
+
+	return missing, extra
+}
+
+// sortOptionCodes sorts codes in ascending order.
+func sortOptionCodes(codes []dhcp6.OptionCode) {
+	sort.Slice(codes, func(i, j int) bool {
+		return codes[i] < codes[j]
+	})
+}
@@ -0,0 +1,88 @@
+package dhcp6opts
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mdlayher/dhcp6"
+)
+
+// TestOptionsDiff verifies that OptionsDiff reports requested options which
+// are missing from a response, and unrequested options which are included.
+func TestOptionsDiff(t *testing.T) {
+	var tests = []struct {
+		desc    string
+		req     dhcp6.Options
+		resp    dhcp6.Options
+		missing []dhcp6.OptionCode
+		extra   []dhcp6.OptionCode
+	}{
+		{
+			desc: "no options",
+		},
+		{
+			desc: "no Option Request Option",
+			resp: dhcp6.Options{
+				dhcp6.OptionPreference: [][]byte{{255}},
+			},
+			extra: []dhcp6.OptionCode{dhcp6.OptionPreference},
+		},
+		{
+			desc: "malformed Option Request Option",
+			req: dhcp6.Options{
+				dhcp6.OptionORO: [][]byte{{0}},
+			},
+		},
+		{
+			desc: "DNS servers requested and returned",
+			req: dhcp6.Options{
+				dhcp6.OptionORO: [][]byte{{0, 23}},
+			},
+			resp: dhcp6.Options{
+				dhcp6.OptionDNSServers: [][]byte{make([]byte, 16)},
+			},
+		},
+		{
+			desc: "DNS servers requested but not returned",
+			req: dhcp6.Options{
+				dhcp6.OptionORO: [][]byte{{0, 23, 0, 59}},
+			},
+			resp: dhcp6.Options{
+				dhcp6.OptionBootFileURL: [][]byte{{0}},
+				dhcp6.OptionPreference:  [][]byte{{255}},
+			},
+			missing: []dhcp6.OptionCode{dhcp6.OptionDNSServers},
+			extra:   []dhcp6.OptionCode{dhcp6.OptionPreference},
+		},
+		{
+			desc: "multiple options missing and extra",
+			req: dhcp6.Options{
+				dhcp6.OptionORO: [][]byte{{0, 59, 0, 23}},
+			},
+			resp: dhcp6.Options{
+				dhcp6.OptionServerID:   [][]byte{{0}},
+				dhcp6.OptionClientID:   [][]byte{{0}},
+				dhcp6.OptionPreference: [][]byte{{255}},
+			},
+			missing: []dhcp6.OptionCode{dhcp6.OptionDNSServers, dhcp6.OptionBootFileURL},
+			extra: []dhcp6.OptionCode{
+				dhcp6.OptionClientID,
+				dhcp6.OptionServerID,
+				dhcp6.OptionPreference,
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		missing, extra := OptionsDiff(tt.req, tt.resp)
+
+		if want, got := tt.missing, missing; !reflect.DeepEqual(want, got) {
+			t.Errorf("[%02d] test %q, unexpected missing options:\n- want: %v\n-  got: %v",
+				i, tt.desc, want, got)
+		}
+		if want, got := tt.extra, extra; !reflect.DeepEqual(want, got) {
+			t.Errorf("[%02d] test %q, unexpected extra options:\n- want: %v\n-  got: %v",
+				i, tt.desc, want, got)
+		}
+	}
+}
@@ -2,7 +2,9 @@ package dhcp6opts
 
 import (
 	"encoding"
+	"encoding/hex"
 	"errors"
+	"fmt"
 	"io"
 	"net"
 	"time"
@@ -30,10 +32,6 @@ var (
 	// to parse a valid DUIDUUID from a byte slice, or when the DUID type
 	// found in the byte slice is incorrect.
 	errInvalidDUIDUUID = errors.New("invalid DUID-UUID")
-
-	// errUnknownDUID is returned when an unknown DUID type is
-	// encountered, and thus, a DUID cannot be parsed.
-	errUnknownDUID = errors.New("unknown DUID type")
 )
 
 var (
@@ -76,6 +74,9 @@ const (
 //   -   DUIDLL - DUID Based on Link-layer Address
 //   - DUIDUUID - DUID Based on Universally Unique Identifier
 //
+// DUIDs of any other type are parsed as an UnknownDUID, which preserves
+// the DUID's raw data.
+//
 // If further introspection of the DUID is needed, a type switch is
 // recommended:
 //	switch d := duid.(type) {
@@ -87,6 +88,8 @@ const (
 //		fmt.Println(d.HardwareAddr)
 //	case *dhcp6.DUIDUUID:
 //		fmt.Println(d.UUID)
+//	case *dhcp6.UnknownDUID:
+//		fmt.Println(d.Data)
 //	}
 type DUID interface {
 	encoding.BinaryMarshaler
@@ -177,6 +180,13 @@ func (d *DUIDLLT) UnmarshalBinary(p []byte) error {
 	return nil
 }
 
+// String returns a human-readable representation of a DUIDLLT, including
+// the absolute time at which it was generated.
+func (d *DUIDLLT) String() string {
+	return fmt.Sprintf("DUID-LLT hwtype=%d time=%s hwaddr=%s",
+		d.HardwareType, duidLLTTime.Add(d.Time).UTC().Format(time.RFC3339), d.HardwareAddr)
+}
+
 // DUIDEN represents a DUID Assigned by Vendor Based on Enterprise Number
 // [DUID-EN], as defined in RFC 3315, Section 9.3.  This DUID type
 // uses an IANA-assigned Private Enterprise Number for a given vendor.
@@ -239,6 +249,12 @@ func (d *DUIDEN) UnmarshalBinary(p []byte) error {
 	return nil
 }
 
+// String returns a human-readable representation of a DUIDEN.
+func (d *DUIDEN) String() string {
+	return fmt.Sprintf("DUID-EN enterprise=%d id=%s",
+		d.EnterpriseNumber, hex.EncodeToString(d.Identifier))
+}
+
 // DUIDLL represents a DUID Based on Link-layer Address [DUID-LL],
 // as defined in RFC 3315, Section 9.4.
 //
@@ -311,6 +327,11 @@ func (d *DUIDLL) UnmarshalBinary(p []byte) error {
 	return nil
 }
 
+// String returns a human-readable representation of a DUIDLL.
+func (d *DUIDLL) String() string {
+	return fmt.Sprintf("DUID-LL hwtype=%d hwaddr=%s", d.HardwareType, d.HardwareAddr)
+}
+
 // DUIDUUID represents a DUID based on Universally Unique Identifier
 // [DUID-UUID], as defined in RFC 6355.  This DUID type uses a UUID to
 // identify clients or servers.
@@ -364,6 +385,45 @@ func (d *DUIDUUID) UnmarshalBinary(p []byte) error {
 	return nil
 }
 
+// UnknownDUID represents a DUID of a type not recognized by this package,
+// such as a DUID type defined after RFC 6355.  The raw data of the DUID is
+// preserved so that it can still be used to identify a client or server.
+type UnknownDUID struct {
+	// Type specifies the DUID type.
+	Type DUIDType
+
+	// Data specifies the raw data which follows the DUID type.
+	Data []byte
+}
+
+// MarshalBinary allocates a byte slice containing the data from an
+// UnknownDUID.
+func (d *UnknownDUID) MarshalBinary() ([]byte, error) {
+	// 2 bytes: DUID type
+	// N bytes: data
+	b := buffer.New(nil)
+
+	b.Write16(uint16(d.Type))
+	b.WriteBytes(d.Data)
+
+	return b.Data(), nil
+}
+
+// UnmarshalBinary unmarshals a raw byte slice into an UnknownDUID.
+// If the byte slice does not contain enough data to determine a DUID type,
+// io.ErrUnexpectedEOF is returned.
+func (d *UnknownDUID) UnmarshalBinary(p []byte) error {
+	b := buffer.New(p)
+	// Too short to contain a DUID type
+	if b.Len() < 2 {
+		return io.ErrUnexpectedEOF
+	}
+
+	d.Type = DUIDType(b.Read16())
+	d.Data = b.Remaining()
+	return nil
+}
+
 // parseDUID returns the correct DUID type of the input byte slice as a
 // DUID interface type.
 func parseDUID(p []byte) (DUID, error) {
@@ -384,8 +444,126 @@ func parseDUID(p []byte) (DUID, error) {
 	case DUIDTypeUUID:
 		d = new(DUIDUUID)
 	default:
-		return nil, errUnknownDUID
+		d = new(UnknownDUID)
 	}
 
 	return d, d.UnmarshalBinary(p)
 }
+
+// MarshalDUID returns the canonical text form of a DUID: each byte of its
+// binary form, including the DUID type, as two lowercase hexadecimal digits
+// separated by colons, such as "00:03:00:01:b8:ae:ed:7a:10:66".
+//
+// The text form is suitable for storing a DUID in a configuration file, and
+// can be reversed using ParseDUID.
+func MarshalDUID(d DUID) (string, error) {
+	b, err := d.MarshalBinary()
+	if err != nil {
+		return "", err
+	}
+
+	s := make([]byte, 0, len(b)*3)
+	for i, c := range b {
+		if i > 0 {
+			s = append(s, ':')
+		}
+		s = append(s, hex.EncodeToString([]byte{c})...)
+	}
+
+	return string(s), nil
+}
+
+// ParseDUID parses a DUID from its canonical text form, as produced by
+// MarshalDUID.  Hexadecimal digits may be upper or lowercase.  The DUID is
+// returned as the DUID type indicated by its first two bytes, in the same
+// way as a DUID received in a Client or Server Identifier option.
+//
+// If s is not in the canonical text form, ErrInvalidDUIDText is returned.
+func ParseDUID(s string) (DUID, error) {
+	b, err := parseDUIDText(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseDUID(b)
+}
+
+// parseDUIDText decodes the colon-separated hexadecimal bytes of a DUID's
+// text form.
+func parseDUIDText(s string) ([]byte, error) {
+	// Each byte is two digits, and every byte but the last is followed by
+	// a colon.
+	if len(s) < 2 || (len(s)+1)%3 != 0 {
+		return nil, ErrInvalidDUIDText
+	}
+
+	b := make([]byte, (len(s)+1)/3)
+	for i := range b {
+		j := i * 3
+		if j+2 < len(s) && s[j+2] != ':' {
+			return nil, ErrInvalidDUIDText
+		}
+
+		if _, err := hex.Decode(b[i:i+1], []byte(s[j:j+2])); err != nil {
+			return nil, ErrInvalidDUIDText
+		}
+	}
+
+	return b, nil
+}
+
+// unmarshalDUIDText implements encoding.TextUnmarshaler for a DUID type.
+func unmarshalDUIDText(d DUID, text []byte) error {
+	b, err := parseDUIDText(string(text))
+	if err != nil {
+		return err
+	}
+
+	return d.UnmarshalBinary(b)
+}
+
+// marshalDUIDText implements encoding.TextMarshaler for a DUID type.
+func marshalDUIDText(d DUID) ([]byte, error) {
+	s, err := MarshalDUID(d)
+	return []byte(s), err
+}
+
+// MarshalText implements encoding.TextMarshaler, using the text form
+// described by MarshalDUID.
+func (d *DUIDLLT) MarshalText() ([]byte, error) { return marshalDUIDText(d) }
+
+// UnmarshalText implements encoding.TextUnmarshaler, using the text form
+// described by MarshalDUID.
+func (d *DUIDLLT) UnmarshalText(text []byte) error { return unmarshalDUIDText(d, text) }
+
+// MarshalText implements encoding.TextMarshaler, using the text form
+// described by MarshalDUID.
+func (d *DUIDEN) MarshalText() ([]byte, error) { return marshalDUIDText(d) }
+
+// UnmarshalText implements encoding.TextUnmarshaler, using the text form
+// described by MarshalDUID.
+func (d *DUIDEN) UnmarshalText(text []byte) error { return unmarshalDUIDText(d, text) }
+
+// MarshalText implements encoding.TextMarshaler, using the text form
+// described by MarshalDUID.
+func (d *DUIDLL) MarshalText() ([]byte, error) { return marshalDUIDText(d) }
+
+// UnmarshalText implements encoding.TextUnmarshaler, using the text form
+// described by MarshalDUID.
+func (d *DUIDLL) UnmarshalText(text []byte) error { return unmarshalDUIDText(d, text) }
+
+// MarshalText implements encoding.TextMarshaler, using the text form
+// described by MarshalDUID.
+func (d *DUIDUUID) MarshalText() ([]byte, error) { return marshalDUIDText(d) }
+
+// UnmarshalText implements encoding.TextUnmarshaler, using the text form
+// described by MarshalDUID.
+func (d *DUIDUUID) UnmarshalText(text []byte) error { return unmarshalDUIDText(d, text) }
+
+// MarshalText implements encoding.TextMarshaler, using the text form
+// described by MarshalDUID.
+func (d *UnknownDUID) MarshalText() ([]byte, error) { return marshalDUIDText(d) }
+
+// UnmarshalText implements encoding.TextUnmarshaler, using the text form
+// described by MarshalDUID.
+func (d *UnknownDUID) UnmarshalText(text []byte) error { return unmarshalDUIDText(d, text) }
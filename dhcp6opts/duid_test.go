@@ -2,11 +2,16 @@ package dhcp6opts
 
 import (
 	"bytes"
+	"encoding/json"
+	"fmt"
 	"io"
 	"net"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/mdlayher/dhcp6"
 )
 
 // TestNewDUIDLLT verifies that NewDUIDLLT generates a proper DUIDLLT or error
@@ -418,6 +423,90 @@ func TestDUIDUUIDUnmarshalBinary(t *testing.T) {
 	}
 }
 
+// TestUnknownDUIDUnmarshalBinary verifies that UnknownDUID.UnmarshalBinary
+// returns appropriate UnknownDUIDs and errors for various input byte slices.
+func TestUnknownDUIDUnmarshalBinary(t *testing.T) {
+	var tests = []struct {
+		desc string
+		buf  []byte
+		duid *UnknownDUID
+		err  error
+	}{
+		{
+			desc: "nil buffer, invalid DUID",
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "length 1 buffer, invalid DUID",
+			buf:  []byte{0},
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "type 99, no data",
+			buf:  []byte{0, 99},
+			duid: &UnknownDUID{
+				Type: 99,
+				Data: []byte{},
+			},
+		},
+		{
+			desc: "type 99, with data",
+			buf:  []byte{0, 99, 1, 2, 3, 4},
+			duid: &UnknownDUID{
+				Type: 99,
+				Data: []byte{1, 2, 3, 4},
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		duid := new(UnknownDUID)
+		if err := duid.UnmarshalBinary(tt.buf); err != nil {
+			if want, got := tt.err, err; want != got {
+				t.Fatalf("[%02d] test %q, unexpected error: %v != %v",
+					i, tt.desc, want, got)
+			}
+
+			continue
+		}
+
+		if want, got := tt.duid, duid; !reflect.DeepEqual(want, got) {
+			t.Fatalf("[%02d] test %q, unexpected unknown DUID:\n- want: %v\n-  got: %v",
+				i, tt.desc, want, got)
+		}
+
+		b, err := duid.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want, got := tt.buf, b; !bytes.Equal(want, got) {
+			t.Fatalf("[%02d] test %q, unexpected unknown DUID bytes:\n- want: %v\n-  got: %v",
+				i, tt.desc, want, got)
+		}
+	}
+}
+
+// TestGetClientIDUnknownDUID verifies that GetClientID returns an UnknownDUID
+// for a DUID type not recognized by this package.
+func TestGetClientIDUnknownDUID(t *testing.T) {
+	o := dhcp6.Options{
+		dhcp6.OptionClientID: [][]byte{{0, 99, 0xde, 0xad, 0xbe, 0xef}},
+	}
+
+	duid, err := GetClientID(o)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &UnknownDUID{
+		Type: 99,
+		Data: []byte{0xde, 0xad, 0xbe, 0xef},
+	}
+	if got := duid; !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected client ID:\n- want: %v\n-  got: %v", want, got)
+	}
+}
+
 // Test_parseDUID verifies that parseDUID detects the correct DUID type for a
 // variety of input data.
 func Test_parseDUID(t *testing.T) {
@@ -431,8 +520,8 @@ func Test_parseDUID(t *testing.T) {
 			err: io.ErrUnexpectedEOF,
 		},
 		{
-			buf: []byte{0, 0},
-			err: errUnknownDUID,
+			buf:    []byte{0, 0},
+			result: reflect.TypeOf(&UnknownDUID{}),
 		},
 		// Known types padded out to be just long enough to not error
 		{
@@ -452,8 +541,8 @@ func Test_parseDUID(t *testing.T) {
 			result: reflect.TypeOf(&DUIDUUID{}),
 		},
 		{
-			buf: []byte{0, 5},
-			err: errUnknownDUID,
+			buf:    []byte{0, 5},
+			result: reflect.TypeOf(&UnknownDUID{}),
 		},
 	}
 
@@ -474,3 +563,187 @@ func Test_parseDUID(t *testing.T) {
 		}
 	}
 }
+
+// TestDUIDString verifies that DUID types produce human-readable strings.
+func TestDUIDString(t *testing.T) {
+	hwaddr := net.HardwareAddr{0xb8, 0xae, 0xed, 0x7a, 0x10, 0x66}
+
+	llt, err := NewDUIDLLT(1, time.Date(2023, time.March, 4, 5, 6, 7, 0, time.UTC), hwaddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = []struct {
+		desc string
+		duid fmt.Stringer
+		s    string
+	}{
+		{
+			desc: "DUID-LLT",
+			duid: llt,
+			s:    "DUID-LLT hwtype=1 time=2023-03-04T05:06:07Z hwaddr=b8:ae:ed:7a:10:66",
+		},
+		{
+			desc: "DUID-EN",
+			duid: NewDUIDEN(32473, []byte{0xde, 0xad, 0xbe, 0xef}),
+			s:    "DUID-EN enterprise=32473 id=deadbeef",
+		},
+		{
+			desc: "DUID-LL",
+			duid: NewDUIDLL(1, hwaddr),
+			s:    "DUID-LL hwtype=1 hwaddr=b8:ae:ed:7a:10:66",
+		},
+	}
+
+	for i, tt := range tests {
+		if want, got := tt.s, tt.duid.String(); want != got {
+			t.Fatalf("[%02d] test %q, unexpected string:\n- want: %q\n-  got: %q",
+				i, tt.desc, want, got)
+		}
+	}
+}
+
+// TestMarshalParseDUID verifies that DUIDs of each type can be round-tripped
+// through their canonical text form.
+func TestMarshalParseDUID(t *testing.T) {
+	hwaddr := net.HardwareAddr{0xb8, 0xae, 0xed, 0x7a, 0x10, 0x66}
+
+	llt, err := NewDUIDLLT(1, time.Date(2023, time.March, 4, 5, 6, 7, 0, time.UTC), hwaddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = []struct {
+		desc string
+		duid DUID
+		s    string
+	}{
+		{
+			desc: "DUID-LLT",
+			duid: llt,
+			s:    "00:01:00:01:2b:95:8e:3f:b8:ae:ed:7a:10:66",
+		},
+		{
+			desc: "DUID-EN",
+			duid: NewDUIDEN(32473, []byte{0xde, 0xad, 0xbe, 0xef}),
+			s:    "00:02:00:00:7e:d9:de:ad:be:ef",
+		},
+		{
+			desc: "DUID-LL",
+			duid: NewDUIDLL(1, hwaddr),
+			s:    "00:03:00:01:b8:ae:ed:7a:10:66",
+		},
+		{
+			desc: "DUID-UUID",
+			duid: NewDUIDUUID([16]byte{15: 1}),
+			s:    "00:04:00:00:00:00:00:00:00:00:00:00:00:00:00:00:00:01",
+		},
+		{
+			desc: "unknown DUID",
+			duid: &UnknownDUID{Type: 99, Data: []byte{0xff}},
+			s:    "00:63:ff",
+		},
+	}
+
+	for i, tt := range tests {
+		s, err := MarshalDUID(tt.duid)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if want, got := tt.s, s; want != got {
+			t.Fatalf("[%02d] test %q, unexpected DUID text:\n- want: %q\n-  got: %q",
+				i, tt.desc, want, got)
+		}
+
+		d, err := ParseDUID(strings.ToUpper(s))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if want, got := tt.duid, d; !reflect.DeepEqual(want, got) {
+			t.Fatalf("[%02d] test %q, unexpected DUID:\n- want: %v\n-  got: %v",
+				i, tt.desc, want, got)
+		}
+	}
+}
+
+// TestParseDUIDErrors verifies that ParseDUID rejects text which is not in
+// the canonical DUID text form.
+func TestParseDUIDErrors(t *testing.T) {
+	var tests = []struct {
+		desc string
+		s    string
+		err  error
+	}{
+		{
+			desc: "empty",
+			err:  ErrInvalidDUIDText,
+		},
+		{
+			desc: "no separators",
+			s:    "000300010203",
+			err:  ErrInvalidDUIDText,
+		},
+		{
+			desc: "wrong separator",
+			s:    "00-03-00-01",
+			err:  ErrInvalidDUIDText,
+		},
+		{
+			desc: "trailing colon",
+			s:    "00:03:",
+			err:  ErrInvalidDUIDText,
+		},
+		{
+			desc: "invalid hexadecimal",
+			s:    "00:zz",
+			err:  ErrInvalidDUIDText,
+		},
+		{
+			desc: "too short for DUID type",
+			s:    "00",
+			err:  io.ErrUnexpectedEOF,
+		},
+	}
+
+	for i, tt := range tests {
+		if _, err := ParseDUID(tt.s); tt.err != err {
+			t.Fatalf("[%02d] test %q, unexpected error: %v != %v",
+				i, tt.desc, tt.err, err)
+		}
+	}
+}
+
+// TestDUIDText verifies that DUID types can be used as text in configuration
+// formats, such as JSON.
+func TestDUIDText(t *testing.T) {
+	var config struct {
+		ServerID *DUIDLL `json:"server_id"`
+	}
+
+	const in = `{"server_id":"00:03:00:01:b8:ae:ed:7a:10:66"}`
+	if err := json.Unmarshal([]byte(in), &config); err != nil {
+		t.Fatal(err)
+	}
+
+	want := NewDUIDLL(1, net.HardwareAddr{0xb8, 0xae, 0xed, 0x7a, 0x10, 0x66})
+	if got := config.ServerID; !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected DUID:\n- want: %v\n-  got: %v", want, got)
+	}
+
+	out, err := json.Marshal(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := in, string(out); want != got {
+		t.Fatalf("unexpected JSON:\n- want: %s\n-  got: %s", want, got)
+	}
+
+	// A DUID of a different type is rejected.
+	var ll DUIDLL
+	if err := ll.UnmarshalText([]byte("00:02:00:00:7e:d9:de:ad:be:ef")); err == nil {
+		t.Fatal("expected an error for DUID-EN text, but none occurred")
+	}
+}
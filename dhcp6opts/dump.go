@@ -0,0 +1,197 @@
+package dhcp6opts
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mdlayher/dhcp6"
+)
+
+// Dump writes a human-readable, hierarchical description of a Packet to w,
+// which is useful for debugging interoperability issues.
+//
+// Each option is written on its own line, indented beneath the packet or
+// option which encapsulates it.  Well-known options are decoded into readable
+// text, and options encapsulated within IANA, IATA, IAPD, IAAddr, IAPrefix,
+// and Relay Message options are described recursively.  Unknown or malformed
+// options are written as hexadecimal.
+func Dump(w io.Writer, p *dhcp6.Packet) error {
+	b := new(bytes.Buffer)
+	fmt.Fprintf(b, "%s, transaction ID: %x\n", p.MessageType, p.TransactionID[:])
+	dumpOptions(b, p.Options, 1)
+
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// dumpOptions writes a description of each option in o to b, in order of
+// option code, using the specified indentation depth.
+func dumpOptions(b *bytes.Buffer, o dhcp6.Options, depth int) {
+	codes := make([]dhcp6.OptionCode, 0, len(o))
+	for code := range o {
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i int, j int) bool {
+		return codes[i] < codes[j]
+	})
+
+	for _, code := range codes {
+		vv := o[code]
+		// A zero-length option may appear with no values at all.
+		if len(vv) == 0 {
+			vv = [][]byte{{}}
+		}
+
+		for _, v := range vv {
+			dumpOption(b, code, v, depth)
+		}
+	}
+}
+
+// dumpOption writes a description of a single option to b, using the
+// specified indentation depth, and recursively describes any options it
+// encapsulates.
+func dumpOption(b *bytes.Buffer, code dhcp6.OptionCode, v []byte, depth int) {
+	indent := strings.Repeat("  ", depth)
+	line := func(format string, args ...interface{}) {
+		fmt.Fprintf(b, "%s%s: %s\n", indent, code, fmt.Sprintf(format, args...))
+	}
+
+	switch code {
+	case dhcp6.OptionClientID, dhcp6.OptionServerID:
+		// DUID types without a String method are described as hexadecimal.
+		if d, err := parseDUID(v); err == nil {
+			if s, ok := d.(fmt.Stringer); ok {
+				line("%s", s)
+				return
+			}
+		}
+	case dhcp6.OptionIANA:
+		ia := new(IANA)
+		if err := ia.UnmarshalBinary(v); err == nil {
+			line("IAID: %x, T1: %s, T2: %s", ia.IAID[:], formatLifetime(ia.T1), formatLifetime(ia.T2))
+			dumpOptions(b, ia.Options, depth+1)
+			return
+		}
+	case dhcp6.OptionIATA:
+		ia := new(IATA)
+		if err := ia.UnmarshalBinary(v); err == nil {
+			line("IAID: %x", ia.IAID[:])
+			dumpOptions(b, ia.Options, depth+1)
+			return
+		}
+	case dhcp6.OptionIAPD:
+		ia := new(IAPD)
+		if err := ia.UnmarshalBinary(v); err == nil {
+			line("IAID: %x, T1: %s, T2: %s", ia.IAID[:], formatLifetime(ia.T1), formatLifetime(ia.T2))
+			dumpOptions(b, ia.Options, depth+1)
+			return
+		}
+	case dhcp6.OptionIAAddr:
+		iaa := new(IAAddr)
+		if err := iaa.UnmarshalBinary(v); err == nil {
+			line("IP: %s, preferred: %s, valid: %s", iaa.IP, formatLifetime(iaa.PreferredLifetime), formatLifetime(iaa.ValidLifetime))
+			dumpOptions(b, iaa.Options, depth+1)
+			return
+		}
+	case dhcp6.OptionIAPrefix:
+		iap := new(IAPrefix)
+		if err := iap.UnmarshalBinary(v); err == nil {
+			line("prefix: %s/%d, preferred: %s, valid: %s", iap.Prefix, iap.PrefixLength, formatLifetime(iap.PreferredLifetime), formatLifetime(iap.ValidLifetime))
+			dumpOptions(b, iap.Options, depth+1)
+			return
+		}
+	case dhcp6.OptionORO:
+		var oro OptionRequestOption
+		if err := oro.UnmarshalBinary(v); err == nil {
+			line("%v", []dhcp6.OptionCode(oro))
+			return
+		}
+	case dhcp6.OptionPreference:
+		var p Preference
+		if err := p.UnmarshalBinary(v); err == nil {
+			line("%d", p)
+			return
+		}
+	case dhcp6.OptionReconfMsg:
+		var r ReconfigureMessage
+		if err := r.UnmarshalBinary(v); err == nil {
+			line("%s", dhcp6.MessageType(r))
+			return
+		}
+	case dhcp6.OptionElapsedTime:
+		var t ElapsedTime
+		if err := t.UnmarshalBinary(v); err == nil {
+			line("%s", time.Duration(t))
+			return
+		}
+	case dhcp6.OptionRelayMsg:
+		if dumpRelayMessage(b, code, v, depth) {
+			return
+		}
+	case dhcp6.OptionUnicast:
+		var ip IP
+		if err := ip.UnmarshalBinary(v); err == nil {
+			line("%s", net.IP(ip))
+			return
+		}
+	case dhcp6.OptionStatusCode:
+		s := new(StatusCode)
+		if err := s.UnmarshalBinary(v); err == nil {
+			line("%s %q", s.Code, s.Message)
+			return
+		}
+	case dhcp6.OptionRapidCommit, dhcp6.OptionReconfAccept:
+		if len(v) == 0 {
+			line("present")
+			return
+		}
+	case dhcp6.OptionDNSServers, dhcp6.OptionSNTPServers:
+		var ips IPs
+		if err := ips.UnmarshalBinary(v); err == nil {
+			line("%v", []net.IP(ips))
+			return
+		}
+	}
+
+	// Unknown or malformed options are described as hexadecimal.
+	line("%s", hex.EncodeToString(v))
+}
+
+// dumpRelayMessage writes a description of the message encapsulated in a
+// Relay Message option to b.  It returns false if the message could not be
+// parsed.
+func dumpRelayMessage(b *bytes.Buffer, code dhcp6.OptionCode, v []byte, depth int) bool {
+	if len(v) == 0 {
+		return false
+	}
+
+	indent := strings.Repeat("  ", depth)
+	switch mt := dhcp6.MessageType(v[0]); mt {
+	case dhcp6.MessageTypeRelayForw, dhcp6.MessageTypeRelayRepl:
+		rm := new(RelayMessage)
+		if err := rm.UnmarshalBinary(v); err != nil {
+			return false
+		}
+
+		fmt.Fprintf(b, "%s%s: %s, hop count: %d, link-address: %s, peer-address: %s\n",
+			indent, code, rm.MessageType, rm.HopCount, rm.LinkAddress, rm.PeerAddress)
+		dumpOptions(b, rm.Options, depth+1)
+	default:
+		p := new(dhcp6.Packet)
+		if err := p.UnmarshalBinary(v); err != nil {
+			return false
+		}
+
+		fmt.Fprintf(b, "%s%s: %s, transaction ID: %x\n", indent, code, p.MessageType, p.TransactionID[:])
+		dumpOptions(b, p.Options, depth+1)
+	}
+
+	return true
+}
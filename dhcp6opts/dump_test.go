@@ -0,0 +1,107 @@
+package dhcp6opts
+
+import (
+	"bytes"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/mdlayher/dhcp6"
+)
+
+// TestDump verifies that Dump writes a hierarchical description of a Packet,
+// including options encapsulated within other options.
+func TestDump(t *testing.T) {
+	iaaddr, err := NewIAAddr(net.ParseIP("2001:db8::1"), 60*time.Second, 90*time.Second, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := iaaddr.Options.Add(dhcp6.OptionStatusCode, NewStatusCode(dhcp6.StatusSuccess, "ok")); err != nil {
+		t.Fatal(err)
+	}
+
+	ia := NewIANA([4]byte{0, 1, 2, 3}, 30*time.Second, 48*time.Second, nil)
+	if err := ia.Options.Add(dhcp6.OptionIAAddr, iaaddr); err != nil {
+		t.Fatal(err)
+	}
+
+	p := &dhcp6.Packet{
+		MessageType:   dhcp6.MessageTypeReply,
+		TransactionID: [3]byte{0xab, 0xcd, 0xef},
+		Options:       make(dhcp6.Options),
+	}
+	p.Options.Add(dhcp6.OptionClientID, NewDUIDLL(1, net.HardwareAddr{0, 1, 0, 1, 0, 1}))
+	p.Options.Add(dhcp6.OptionServerID, NewDUIDUUID([16]byte{}))
+	p.Options.Add(dhcp6.OptionIANA, ia)
+	p.Options.Add(dhcp6.OptionRapidCommit, nil)
+	p.Options.AddRaw(65000, []byte{0xde, 0xad})
+
+	want := `MessageTypeReply, transaction ID: abcdef
+  OptionClientID: DUID-LL hwtype=1 hwaddr=00:01:00:01:00:01
+  OptionServerID: 000400000000000000000000000000000000
+  OptionIANA: IAID: 00010203, T1: 30s, T2: 48s
+    OptionIAAddr: IP: 2001:db8::1, preferred: 1m0s, valid: 1m30s
+      OptionStatusCode: StatusSuccess "ok"
+  OptionRapidCommit: present
+  OptionCode(65000): dead
+`
+
+	b := new(bytes.Buffer)
+	if err := Dump(b, p); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := b.String(); want != got {
+		t.Fatalf("unexpected dump:\n- want:\n%s\n-  got:\n%s", want, got)
+	}
+}
+
+// TestDumpRelayMessage verifies that Dump describes the message encapsulated
+// in a Relay Message option.
+func TestDumpRelayMessage(t *testing.T) {
+	inner := &dhcp6.Packet{
+		MessageType:   dhcp6.MessageTypeAdvertise,
+		TransactionID: [3]byte{1, 2, 3},
+		Options:       make(dhcp6.Options),
+	}
+	inner.Options.Add(dhcp6.OptionPreference, Preference(255))
+
+	var rmo RelayMessageOption
+	if err := rmo.SetClientServerMessage(inner); err != nil {
+		t.Fatal(err)
+	}
+
+	rm := &RelayMessage{
+		MessageType: dhcp6.MessageTypeRelayRepl,
+		LinkAddress: net.ParseIP("2001:db8::1"),
+		PeerAddress: net.ParseIP("fe80::1"),
+		Options:     make(dhcp6.Options),
+	}
+	rm.Options.Add(dhcp6.OptionRelayMsg, &rmo)
+
+	var outer RelayMessageOption
+	if err := outer.SetRelayMessage(rm); err != nil {
+		t.Fatal(err)
+	}
+
+	p := &dhcp6.Packet{
+		MessageType: dhcp6.MessageTypeReply,
+		Options:     make(dhcp6.Options),
+	}
+	p.Options.Add(dhcp6.OptionRelayMsg, &outer)
+
+	want := `MessageTypeReply, transaction ID: 000000
+  OptionRelayMsg: MessageTypeRelayRepl, hop count: 0, link-address: 2001:db8::1, peer-address: fe80::1
+    OptionRelayMsg: MessageTypeAdvertise, transaction ID: 010203
+      OptionPreference: 255
+`
+
+	b := new(bytes.Buffer)
+	if err := Dump(b, p); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := b.String(); want != got {
+		t.Fatalf("unexpected dump:\n- want:\n%s\n-  got:\n%s", want, got)
+	}
+}
@@ -1,6 +1,7 @@
 package dhcp6opts
 
 import (
+	"fmt"
 	"io"
 	"net"
 	"time"
@@ -53,7 +54,8 @@ type IAAddr struct {
 // If an Options map is not specified, a new one will be allocated.
 func NewIAAddr(ip net.IP, preferred time.Duration, valid time.Duration, options dhcp6.Options) (*IAAddr, error) {
 	// From documentation: If ip is not an IPv4 address, To4 returns nil.
-	if ip.To4() != nil {
+	// To16 returns nil for a nil or otherwise malformed address.
+	if ip.To16() == nil || ip.To4() != nil {
 		return nil, ErrInvalidIP
 	}
 
@@ -76,14 +78,23 @@ func NewIAAddr(ip net.IP, preferred time.Duration, valid time.Duration, options
 }
 
 // MarshalBinary allocates a byte slice containing the data from a IAAddr.
+//
+// If IP is not an IPv6 address, such as a nil or IPv4 address, ErrInvalidIP
+// is returned.  If any option is too large to be encoded,
+// dhcp6.ErrOptionTooLarge is returned.
 func (i *IAAddr) MarshalBinary() ([]byte, error) {
 	// 16 bytes: IPv6 address
 	//  4 bytes: preferred lifetime
 	//  4 bytes: valid lifetime
 	//  N bytes: options
+	ip := i.IP.To16()
+	if len(ip) != net.IPv6len || i.IP.To4() != nil {
+		return nil, ErrInvalidIP
+	}
+
 	b := buffer.New(nil)
 
-	copy(b.WriteN(net.IPv6len), i.IP)
+	copy(b.WriteN(net.IPv6len), ip)
 	b.Write32(uint32(i.PreferredLifetime / time.Second))
 	b.Write32(uint32(i.ValidLifetime / time.Second))
 	opts, err := i.Options.MarshalBinary()
@@ -119,3 +130,11 @@ func (i *IAAddr) UnmarshalBinary(p []byte) error {
 
 	return (&i.Options).UnmarshalBinary(b.Remaining())
 }
+
+// String returns a human-readable representation of an IAAddr, such as
+// "IAADDR 2001:db8::1 preferred=1h0m0s valid=infinite".  Infinite
+// lifetimes are rendered as "infinite".
+func (i *IAAddr) String() string {
+	return fmt.Sprintf("IAADDR %s preferred=%s valid=%s",
+		i.IP, formatLifetime(i.PreferredLifetime), formatLifetime(i.ValidLifetime))
+}
@@ -23,8 +23,8 @@ func TestNewIAAddr(t *testing.T) {
 		err       error
 	}{
 		{
-			desc:   "all zero values",
-			iaaddr: &IAAddr{},
+			desc: "all zero values",
+			err:  ErrInvalidIP,
 		},
 		{
 			desc: "IPv4 address",
@@ -1,7 +1,9 @@
 package dhcp6opts
 
 import (
+	"fmt"
 	"io"
+	"math"
 	"time"
 
 	"github.com/mdlayher/dhcp6"
@@ -33,6 +35,37 @@ type IANA struct {
 	Options dhcp6.Options
 }
 
+// InfiniteLifetime is the lifetime value which represents infinity, as
+// described in RFC 3315, Section 9.
+const InfiniteLifetime = time.Duration(math.MaxUint32) * time.Second
+
+// formatLifetime formats a lifetime, T1, or T2 duration for display,
+// rendering InfiniteLifetime as "infinite".
+func formatLifetime(d time.Duration) string {
+	if d >= InfiniteLifetime {
+		return "infinite"
+	}
+
+	return d.String()
+}
+
+// RecommendedT1T2 returns T1 and T2 durations for an IANA, using the
+// fractions of 0.5 and 0.8 of the preferred lifetime of its addresses, as
+// recommended in RFC 3315, Section 22.4.
+//
+// If the preferred lifetime is InfiniteLifetime, T1 and T2 are also
+// InfiniteLifetime.  If the preferred lifetime is zero, T1 and T2 are zero,
+// leaving their values to the discretion of the client.
+func RecommendedT1T2(preferred time.Duration) (t1 time.Duration, t2 time.Duration) {
+	if preferred >= InfiniteLifetime {
+		return InfiniteLifetime, InfiniteLifetime
+	}
+
+	// Compute 0.8 of the preferred lifetime without multiplying first, which
+	// would overflow for lifetimes of many years.
+	return preferred / 2, preferred - preferred/5
+}
+
 // NewIANA creates a new IANA from an IAID, T1 and T2 durations, and an
 // Options map.  If an Options map is not specified, a new one will be
 // allocated.
@@ -86,3 +119,129 @@ func (i *IANA) UnmarshalBinary(p []byte) error {
 
 	return (&i.Options).UnmarshalBinary(b.Remaining())
 }
+
+// Validate checks that an IANA's T1 and T2 values are consistent, as
+// described in RFC 3315, Section 22.4.  T1 must not be greater than T2,
+// unless T2 is zero, which leaves both values to the discretion of the
+// client.  A server may call Validate to catch misconfigured lifetimes
+// before sending an IANA.
+//
+// If T1 is greater than a non-zero T2, ErrInvalidT1T2 is returned.
+func (i *IANA) Validate() error {
+	return validateT1T2(i.T1, i.T2)
+}
+
+// validateT1T2 verifies that t1 is not greater than t2, unless t2 is zero.
+func validateT1T2(t1, t2 time.Duration) error {
+	if t2 != 0 && t1 > t2 {
+		return ErrInvalidT1T2
+	}
+
+	return nil
+}
+
+// SetAddresses replaces any IAAddr values encapsulated in the Options map of
+// an IANA with addrs, in order.  Multiple addresses may be assigned to a
+// single IANA, as described in RFC 3315, Section 22.4.
+//
+// If an IAAddr cannot be marshaled, its error is returned.
+func (i *IANA) SetAddresses(addrs ...*IAAddr) error {
+	if i.Options == nil {
+		i.Options = make(dhcp6.Options)
+	}
+
+	delete(i.Options, dhcp6.OptionIAAddr)
+	for _, a := range addrs {
+		if err := i.Options.Add(dhcp6.OptionIAAddr, a); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// IAAddrs returns the IAAddr values encapsulated in the Options map of an
+// IANA.  If no IAAddr values are present, dhcp6.ErrOptionNotPresent is
+// returned.
+//
+// If an IAAddr cannot be parsed, an *IAAddrError is returned, which
+// identifies the IAID of the IANA which held the malformed IAAddr.
+func (i *IANA) IAAddrs() ([]*IAAddr, error) {
+	iaaddrs, err := GetIAAddr(i.Options)
+	switch err {
+	case nil:
+		return iaaddrs, nil
+	case dhcp6.ErrOptionNotPresent:
+		return nil, err
+	default:
+		return nil, &IAAddrError{
+			IAID: i.IAID,
+			Err:  err,
+		}
+	}
+}
+
+// IsRenewal reports whether an IANA contains at least one IAAddr, indicating
+// that a client is renewing, rebinding, or confirming addresses which were
+// previously assigned to it, rather than soliciting new addresses.
+//
+// IsRenewal does not parse the IAAddr values; use IAAddrs to retrieve them.
+func (i *IANA) IsRenewal() bool {
+	return len(i.Options[dhcp6.OptionIAAddr]) > 0
+}
+
+// FailIA returns a copy of ia which can be returned to a client when a
+// server cannot assign addresses to ia, as described in RFC 3315,
+// Section 17.2.2.  The copy preserves the IAID, T1, T2, and other options
+// of ia, but any addresses are removed, and any Status Code option is
+// replaced with a Status Code option using status and message.
+//
+// ia is not modified.
+func FailIA(ia *IANA, status dhcp6.Status, message string) *IANA {
+	options := make(dhcp6.Options, len(ia.Options))
+	for code, values := range ia.Options {
+		if code == dhcp6.OptionIAAddr || code == dhcp6.OptionStatusCode {
+			continue
+		}
+
+		// Copy each value so the IAs do not share option data.
+		vv := make([][]byte, 0, len(values))
+		for _, v := range values {
+			vv = append(vv, append([]byte(nil), v...))
+		}
+		options[code] = vv
+	}
+
+	// Status codes never fail to marshal.
+	_ = options.Add(dhcp6.OptionStatusCode, NewStatusCode(status, message))
+
+	return NewIANA(ia.IAID, ia.T1, ia.T2, options)
+}
+
+// An IAAddrError is returned when an IAAddr encapsulated within an IANA
+// cannot be parsed.
+type IAAddrError struct {
+	// IAID specifies the IAID of the IANA which held the malformed IAAddr.
+	IAID [4]byte
+
+	// Err specifies the error which occurred while parsing the IAAddr.
+	Err error
+}
+
+// Error implements error.
+func (e *IAAddrError) Error() string {
+	return fmt.Sprintf("invalid IAAddr in IANA with IAID %x: %v", e.IAID, e.Err)
+}
+
+// Unwrap returns the error which occurred while parsing the IAAddr.
+func (e *IAAddrError) Unwrap() error {
+	return e.Err
+}
+
+// String returns a human-readable representation of an IANA, such as
+// "IA_NA IAID=01020304 T1=30m0s T2=48m0s".  Infinite durations are
+// rendered as "infinite".
+func (i *IANA) String() string {
+	return fmt.Sprintf("IA_NA IAID=%x T1=%s T2=%s",
+		i.IAID[:], formatLifetime(i.T1), formatLifetime(i.T2))
+}
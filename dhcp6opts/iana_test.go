@@ -2,8 +2,12 @@ package dhcp6opts
 
 import (
 	"bytes"
+	"encoding"
+	"fmt"
 	"io"
+	"net"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -162,6 +166,19 @@ func TestIANAUnmarshalBinary(t *testing.T) {
 			},
 			err: dhcp6.ErrInvalidOptions,
 		},
+		{
+			buf: []byte{
+				1, 2, 3, 4,
+				0, 0, 1, 0,
+				0, 0, 2, 0,
+			},
+			iana: &IANA{
+				IAID:    [4]byte{1, 2, 3, 4},
+				T1:      (4 * time.Minute) + 16*time.Second,
+				T2:      (8 * time.Minute) + 32*time.Second,
+				Options: dhcp6.Options{},
+			},
+		},
 		{
 			buf: []byte{
 				1, 2, 3, 4,
@@ -197,3 +214,496 @@ func TestIANAUnmarshalBinary(t *testing.T) {
 		}
 	}
 }
+
+// TestIANAUnmarshalBinaryNoOptions verifies that an IANA with no options
+// has an initialized Options map, so options may be added to it immediately.
+func TestIANAUnmarshalBinaryNoOptions(t *testing.T) {
+	iana := new(IANA)
+	if err := iana.UnmarshalBinary(make([]byte, 12)); err != nil {
+		t.Fatal(err)
+	}
+
+	if iana.Options == nil {
+		t.Fatal("IANA.Options is nil")
+	}
+
+	if err := iana.Options.Add(dhcp6.OptionStatusCode, NewStatusCode(dhcp6.StatusSuccess, "ok")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestRecommendedT1T2 verifies that RecommendedT1T2 returns the recommended
+// fractions of a preferred lifetime for T1 and T2.
+func TestRecommendedT1T2(t *testing.T) {
+	var tests = []struct {
+		desc      string
+		preferred time.Duration
+		t1        time.Duration
+		t2        time.Duration
+	}{
+		{
+			desc: "zero preferred lifetime",
+		},
+		{
+			desc:      "100 second preferred lifetime",
+			preferred: 100 * time.Second,
+			t1:        50 * time.Second,
+			t2:        80 * time.Second,
+		},
+		{
+			desc:      "1 hour preferred lifetime",
+			preferred: 1 * time.Hour,
+			t1:        30 * time.Minute,
+			t2:        48 * time.Minute,
+		},
+		{
+			desc:      "100 year preferred lifetime",
+			preferred: 100 * 365 * 24 * time.Hour,
+			t1:        50 * 365 * 24 * time.Hour,
+			t2:        80 * 365 * 24 * time.Hour,
+		},
+		{
+			desc:      "largest finite preferred lifetime",
+			preferred: InfiniteLifetime - time.Second,
+			t1:        (InfiniteLifetime - time.Second) / 2,
+			t2:        (InfiniteLifetime - time.Second) - (InfiniteLifetime-time.Second)/5,
+		},
+		{
+			desc:      "infinite preferred lifetime",
+			preferred: InfiniteLifetime,
+			t1:        InfiniteLifetime,
+			t2:        InfiniteLifetime,
+		},
+	}
+
+	for i, tt := range tests {
+		t1, t2 := RecommendedT1T2(tt.preferred)
+		if t1 < 0 || t1 > t2 || t2 > tt.preferred {
+			t.Errorf("[%02d] test %q, expected 0 <= T1 <= T2 <= preferred for RecommendedT1T2(%v), but got T1: %v, T2: %v",
+				i, tt.desc, tt.preferred, t1, t2)
+		}
+		if want, got := tt.t1, t1; want != got {
+			t.Errorf("[%02d] test %q, unexpected T1 for RecommendedT1T2(%v): %v != %v",
+				i, tt.desc, tt.preferred, want, got)
+		}
+		if want, got := tt.t2, t2; want != got {
+			t.Errorf("[%02d] test %q, unexpected T2 for RecommendedT1T2(%v): %v != %v",
+				i, tt.desc, tt.preferred, want, got)
+		}
+	}
+}
+
+// TestIANAIAAddrs verifies that IANA.IAAddrs returns the IAAddr values
+// encapsulated within an IANA, and identifies the IANA in errors returned
+// for malformed IAAddr values.
+func TestIANAIAAddrs(t *testing.T) {
+	iaid := [4]byte{0xde, 0xad, 0xbe, 0xef}
+
+	var tests = []struct {
+		desc    string
+		options dhcp6.Options
+		iaaddrs []*IAAddr
+		err     error
+	}{
+		{
+			desc: "no IAAddr",
+			err:  dhcp6.ErrOptionNotPresent,
+		},
+		{
+			desc: "malformed IAAddr",
+			options: dhcp6.Options{
+				dhcp6.OptionIAAddr: [][]byte{bytes.Repeat([]byte{0}, 23)},
+			},
+			err: &IAAddrError{
+				IAID: iaid,
+				Err:  io.ErrUnexpectedEOF,
+			},
+		},
+		{
+			desc: "one IAAddr",
+			options: dhcp6.Options{
+				dhcp6.OptionIAAddr: [][]byte{bytes.Repeat([]byte{0}, 24)},
+			},
+			iaaddrs: []*IAAddr{
+				{
+					IP:      make([]byte, 16),
+					Options: dhcp6.Options{},
+				},
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		ia := NewIANA(iaid, 0, 0, tt.options)
+		iaaddrs, err := ia.IAAddrs()
+		if err != nil {
+			if want, got := tt.err, err; !reflect.DeepEqual(want, got) {
+				t.Errorf("[%02d] test %q, unexpected error for IANA.IAAddrs(): %v != %v",
+					i, tt.desc, want, got)
+			}
+			continue
+		}
+
+		if want, got := tt.iaaddrs, iaaddrs; !reflect.DeepEqual(want, got) {
+			t.Errorf("[%02d] test %q, unexpected value for IANA.IAAddrs():\n- want: %v\n-  got: %v",
+				i, tt.desc, want, got)
+		}
+	}
+}
+
+// TestIANASetAddresses verifies that IANA.SetAddresses encapsulates
+// multiple IAAddr values in an IANA, which IANA.IAAddrs reads back after
+// a round trip through the wire format.
+func TestIANASetAddresses(t *testing.T) {
+	newAddr := func(ip string) *IAAddr {
+		a, err := NewIAAddr(net.ParseIP(ip), time.Hour, 2*time.Hour, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return a
+	}
+
+	addrs := []*IAAddr{
+		newAddr("2001:db8::1"),
+		newAddr("2001:db8::2"),
+	}
+
+	iana := &IANA{IAID: [4]byte{1, 2, 3, 4}}
+
+	// Existing addresses are replaced
+	if err := iana.SetAddresses(newAddr("2001:db8::ff")); err != nil {
+		t.Fatal(err)
+	}
+	if err := iana.SetAddresses(addrs...); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := iana.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := new(IANA)
+	if err := got.UnmarshalBinary(b); err != nil {
+		t.Fatal(err)
+	}
+
+	iaaddrs, err := got.IAAddrs()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := addrs, iaaddrs; !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected IAAddrs:\n- want: %v\n-  got: %v", want, got)
+	}
+}
+
+// TestIANAIsRenewal verifies that IANA.IsRenewal reports whether an IANA
+// contains an IAAddr.
+func TestIANAIsRenewal(t *testing.T) {
+	iaaddr, err := NewIAAddr(net.ParseIP("2001:db8::1"), time.Hour, 2*time.Hour, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	renew := NewIANA([4]byte{1, 2, 3, 4}, 0, 0, nil)
+	if err := renew.SetAddresses(iaaddr); err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = []struct {
+		desc string
+		iana *IANA
+		ok   bool
+	}{
+		{
+			desc: "solicit, nil options",
+			iana: &IANA{IAID: [4]byte{1, 2, 3, 4}},
+		},
+		{
+			desc: "solicit, no IAAddr",
+			iana: NewIANA([4]byte{1, 2, 3, 4}, 0, 0, dhcp6.Options{
+				dhcp6.OptionStatusCode: [][]byte{{0, 0}},
+			}),
+		},
+		{
+			desc: "renew, one IAAddr",
+			iana: renew,
+			ok:   true,
+		},
+	}
+
+	for i, tt := range tests {
+		if want, got := tt.ok, tt.iana.IsRenewal(); want != got {
+			t.Fatalf("[%02d] test %q, unexpected IANA.IsRenewal(): %v != %v",
+				i, tt.desc, want, got)
+		}
+	}
+}
+
+// TestIAMarshalBinaryErrors verifies that IA types report errors when their
+// contents cannot be encoded.
+func TestIAMarshalBinaryErrors(t *testing.T) {
+	large := dhcp6.Options{
+		dhcp6.OptionUserClass: [][]byte{make([]byte, 65536)},
+	}
+
+	var tests = []struct {
+		desc string
+		m    encoding.BinaryMarshaler
+		err  error
+	}{
+		{
+			desc: "IANA with oversized option",
+			m:    NewIANA([4]byte{1}, 0, 0, large),
+			err:  dhcp6.ErrOptionTooLarge,
+		},
+		{
+			desc: "IAPD with oversized option",
+			m:    NewIAPD([4]byte{1}, 0, 0, large),
+			err:  dhcp6.ErrOptionTooLarge,
+		},
+		{
+			desc: "IAAddr with oversized option",
+			m: &IAAddr{
+				IP:      net.ParseIP("2001:db8::1"),
+				Options: large,
+			},
+			err: dhcp6.ErrOptionTooLarge,
+		},
+		{
+			desc: "IAAddr with IPv4 address",
+			m: &IAAddr{
+				IP: net.IPv4(192, 0, 2, 1),
+			},
+			err: ErrInvalidIP,
+		},
+		{
+			desc: "IAAddr with nil address",
+			m:    &IAAddr{},
+			err:  ErrInvalidIP,
+		},
+		{
+			desc: "IAAddr with short address",
+			m: &IAAddr{
+				IP: net.IP{0x20, 0x01, 0x0d, 0xb8},
+			},
+			err: ErrInvalidIP,
+		},
+		{
+			desc: "IAPrefix with IPv4 prefix",
+			m: &IAPrefix{
+				Prefix: net.IPv4(192, 0, 2, 0),
+			},
+			err: ErrInvalidIP,
+		},
+		{
+			desc: "IAPrefix with nil prefix",
+			m:    &IAPrefix{},
+			err:  ErrInvalidIP,
+		},
+	}
+
+	for i, tt := range tests {
+		if _, err := tt.m.MarshalBinary(); tt.err != err {
+			t.Fatalf("[%02d] test %q, unexpected error: %v != %v",
+				i, tt.desc, tt.err, err)
+		}
+	}
+
+	// An IANA whose options fit, but which is too large to be carried in
+	// an option itself, is rejected when its Options map is marshaled.
+	ia := NewIANA([4]byte{1}, 0, 0, dhcp6.Options{
+		dhcp6.OptionUserClass: [][]byte{make([]byte, 65535)},
+	})
+	o := make(dhcp6.Options)
+	if err := o.Add(dhcp6.OptionIANA, ia); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := o.MarshalBinary(); err != dhcp6.ErrOptionTooLarge {
+		t.Fatalf("unexpected error marshaling oversized IANA: %v", err)
+	}
+}
+
+// TestIAValidate verifies that IANA.Validate and IAPD.Validate reject a T1
+// value greater than a non-zero T2 value.
+func TestIAValidate(t *testing.T) {
+	var tests = []struct {
+		desc   string
+		t1, t2 time.Duration
+		err    error
+	}{
+		{
+			desc: "T1 greater than T2",
+			t1:   100 * time.Second,
+			t2:   50 * time.Second,
+			err:  ErrInvalidT1T2,
+		},
+		{
+			desc: "T1 less than T2",
+			t1:   50 * time.Second,
+			t2:   100 * time.Second,
+		},
+		{
+			desc: "T1 equal to T2",
+			t1:   100 * time.Second,
+			t2:   100 * time.Second,
+		},
+		{
+			desc: "T2 unspecified",
+			t1:   100 * time.Second,
+		},
+		{
+			desc: "both unspecified",
+		},
+	}
+
+	for i, tt := range tests {
+		if want, got := tt.err, NewIANA([4]byte{1}, tt.t1, tt.t2, nil).Validate(); want != got {
+			t.Fatalf("[%02d] test %q, unexpected IANA error: %v != %v",
+				i, tt.desc, want, got)
+		}
+		if want, got := tt.err, NewIAPD([4]byte{1}, tt.t1, tt.t2, nil).Validate(); want != got {
+			t.Fatalf("[%02d] test %q, unexpected IAPD error: %v != %v",
+				i, tt.desc, want, got)
+		}
+	}
+}
+
+// TestIAAddrErrorIncludesIAID verifies that an IAAddrError's message
+// identifies the IAID of the IANA which held a malformed IAAddr.
+func TestIAAddrErrorIncludesIAID(t *testing.T) {
+	ia := NewIANA([4]byte{0xde, 0xad, 0xbe, 0xef}, 0, 0, dhcp6.Options{
+		dhcp6.OptionIAAddr: [][]byte{{0}},
+	})
+
+	_, err := ia.IAAddrs()
+	if err == nil {
+		t.Fatal("expected an error for malformed IAAddr, but none occurred")
+	}
+
+	if want, got := "deadbeef", err.Error(); !strings.Contains(got, want) {
+		t.Fatalf("error %q does not contain IAID %q", got, want)
+	}
+}
+
+// TestFailIA verifies that FailIA returns a copy of an IANA which preserves
+// its IAID, removes its addresses, and carries the input status.
+func TestFailIA(t *testing.T) {
+	addr, err := NewIAAddr(net.ParseIP("2001:db8::1"), 1*time.Hour, 2*time.Hour, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	options := make(dhcp6.Options)
+	if err := options.Add(dhcp6.OptionIAAddr, addr); err != nil {
+		t.Fatal(err)
+	}
+	if err := options.Add(dhcp6.OptionStatusCode, NewStatusCode(dhcp6.StatusSuccess, "")); err != nil {
+		t.Fatal(err)
+	}
+	options.AddRaw(65000, []byte{1})
+
+	ia := NewIANA([4]byte{0, 1, 2, 3}, 30*time.Minute, 48*time.Minute, options)
+
+	failed := FailIA(ia, dhcp6.StatusNoAddrsAvail, "no addresses available")
+
+	if want, got := ia.IAID, failed.IAID; want != got {
+		t.Fatalf("unexpected IAID: %v != %v", want, got)
+	}
+	if want, got := ia.T1, failed.T1; want != got {
+		t.Fatalf("unexpected T1: %v != %v", want, got)
+	}
+	if want, got := ia.T2, failed.T2; want != got {
+		t.Fatalf("unexpected T2: %v != %v", want, got)
+	}
+
+	if _, err := failed.IAAddrs(); err != dhcp6.ErrOptionNotPresent {
+		t.Fatalf("unexpected error for IAAddrs: %v != %v", dhcp6.ErrOptionNotPresent, err)
+	}
+
+	sc, err := GetStatusCode(failed.Options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := NewStatusCode(dhcp6.StatusNoAddrsAvail, "no addresses available"), sc; !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected status code:\n- want: %v\n-  got: %v", want, got)
+	}
+
+	if want, got := [][]byte{{1}}, failed.Options[65000]; !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected unknown option:\n- want: %v\n-  got: %v", want, got)
+	}
+
+	// The input IANA must not be modified.
+	if _, err := ia.IAAddrs(); err != nil {
+		t.Fatalf("unexpected error for input IANA IAAddrs: %v", err)
+	}
+	sc, err = GetStatusCode(ia.Options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := dhcp6.StatusSuccess, sc.Code; want != got {
+		t.Fatalf("unexpected input IANA status: %v != %v", want, got)
+	}
+
+	// Modifying the failure IANA's option values must not modify the input.
+	failed.Options[65000][0][0] = 2
+	if want, got := [][]byte{{1}}, ia.Options[65000]; !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected input IANA unknown option:\n- want: %v\n-  got: %v", want, got)
+	}
+}
+
+// TestIAString verifies that the String methods of the IA types and their
+// addresses and prefixes render infinite durations as "infinite".
+func TestIAString(t *testing.T) {
+	var tests = []struct {
+		desc string
+		s    fmt.Stringer
+		out  string
+	}{
+		{
+			desc: "IANA",
+			s: &IANA{
+				IAID: [4]byte{1, 2, 3, 4},
+				T1:   30 * time.Minute,
+				T2:   InfiniteLifetime,
+			},
+			out: "IA_NA IAID=01020304 T1=30m0s T2=infinite",
+		},
+		{
+			desc: "IAPD",
+			s: &IAPD{
+				IAID: [4]byte{1, 2, 3, 4},
+				T1:   InfiniteLifetime,
+				T2:   InfiniteLifetime,
+			},
+			out: "IA_PD IAID=01020304 T1=infinite T2=infinite",
+		},
+		{
+			desc: "IAAddr",
+			s: &IAAddr{
+				IP:                net.ParseIP("2001:db8::1"),
+				PreferredLifetime: time.Hour,
+				ValidLifetime:     InfiniteLifetime,
+			},
+			out: "IAADDR 2001:db8::1 preferred=1h0m0s valid=infinite",
+		},
+		{
+			desc: "IAPrefix",
+			s: &IAPrefix{
+				Prefix:            net.ParseIP("2001:db8::"),
+				PrefixLength:      56,
+				PreferredLifetime: InfiniteLifetime,
+				ValidLifetime:     InfiniteLifetime,
+			},
+			out: "IAPREFIX 2001:db8::/56 preferred=infinite valid=infinite",
+		},
+	}
+
+	for i, tt := range tests {
+		if want, got := tt.out, tt.s.String(); want != got {
+			t.Fatalf("[%02d] test %q, unexpected String output:\n- want: %v\n-  got: %v",
+				i, tt.desc, want, got)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package dhcp6opts
 
 import (
+	"fmt"
 	"io"
 	"time"
 
@@ -86,3 +87,36 @@ func (i *IAPD) UnmarshalBinary(b []byte) error {
 
 	return (&i.Options).UnmarshalBinary(buf.Remaining())
 }
+
+// Validate checks that an IAPD's T1 and T2 values are consistent, as
+// described in RFC 3633, Section 9.  T1 must not be greater than T2, unless
+// T2 is zero, which leaves both values to the discretion of the requesting
+// router.
+//
+// If T1 is greater than a non-zero T2, ErrInvalidT1T2 is returned.
+func (i *IAPD) Validate() error {
+	return validateT1T2(i.T1, i.T2)
+}
+
+// Prefixes returns the IAPrefix values encapsulated in the Options map of an
+// IAPD, which describe the prefixes delegated to a requesting router.  If no
+// IAPrefix values are present, dhcp6.ErrOptionNotPresent is returned.
+func (i *IAPD) Prefixes() ([]*IAPrefix, error) {
+	return GetIAPrefix(i.Options)
+}
+
+// Status returns the StatusCode encapsulated in the Options map of an IAPD,
+// such as dhcp6.StatusNoPrefixAvail when a delegating router has no prefixes
+// available.  If no StatusCode is present, dhcp6.ErrOptionNotPresent is
+// returned.
+func (i *IAPD) Status() (*StatusCode, error) {
+	return GetStatusCode(i.Options)
+}
+
+// String returns a human-readable representation of an IAPD, such as
+// "IA_PD IAID=01020304 T1=30m0s T2=48m0s".  Infinite durations are
+// rendered as "infinite".
+func (i *IAPD) String() string {
+	return fmt.Sprintf("IA_PD IAID=%x T1=%s T2=%s",
+		i.IAID[:], formatLifetime(i.T1), formatLifetime(i.T2))
+}
@@ -3,6 +3,7 @@ package dhcp6opts
 import (
 	"bytes"
 	"io"
+	"net"
 	"reflect"
 	"testing"
 	"time"
@@ -89,6 +90,19 @@ func TestIAPDUnmarshalBinary(t *testing.T) {
 			},
 			err: dhcp6.ErrInvalidOptions,
 		},
+		{
+			buf: []byte{
+				1, 2, 3, 4,
+				0, 0, 1, 0,
+				0, 0, 2, 0,
+			},
+			iapd: &IAPD{
+				IAID:    [4]byte{1, 2, 3, 4},
+				T1:      (4 * time.Minute) + 16*time.Second,
+				T2:      (8 * time.Minute) + 32*time.Second,
+				Options: dhcp6.Options{},
+			},
+		},
 		{
 			buf: []byte{
 				1, 2, 3, 4,
@@ -124,3 +138,92 @@ func TestIAPDUnmarshalBinary(t *testing.T) {
 		}
 	}
 }
+
+// TestIAPDPrefixesStatus verifies that IAPD.Prefixes and IAPD.Status return
+// the IAPrefix and StatusCode values encapsulated within an IAPD.
+func TestIAPDPrefixesStatus(t *testing.T) {
+	prefix, err := NewIAPrefix(1*time.Hour, 2*time.Hour, 56, net.ParseIP("2001:db8::"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sc := NewStatusCode(dhcp6.StatusNoPrefixAvail, "no prefixes available")
+
+	var tests = []struct {
+		desc     string
+		prefixes []*IAPrefix
+		sc       *StatusCode
+	}{
+		{
+			desc: "no prefixes or status",
+		},
+		{
+			desc:     "prefix",
+			prefixes: []*IAPrefix{prefix},
+		},
+		{
+			desc: "status",
+			sc:   sc,
+		},
+		{
+			desc:     "prefix and status",
+			prefixes: []*IAPrefix{prefix},
+			sc:       sc,
+		},
+	}
+
+	for i, tt := range tests {
+		options := make(dhcp6.Options)
+		for _, p := range tt.prefixes {
+			if err := options.Add(dhcp6.OptionIAPrefix, p); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if tt.sc != nil {
+			if err := options.Add(dhcp6.OptionStatusCode, tt.sc); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		// Round-trip the IAPD, as a requesting router would receive it.
+		b, err := NewIAPD([4]byte{0, 1, 2, 3}, 30*time.Minute, 48*time.Minute, options).MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		iapd := new(IAPD)
+		if err := iapd.UnmarshalBinary(b); err != nil {
+			t.Fatal(err)
+		}
+
+		prefixes, err := iapd.Prefixes()
+		if tt.prefixes == nil {
+			if want, got := dhcp6.ErrOptionNotPresent, err; want != got {
+				t.Fatalf("[%02d] test %q, unexpected error for IAPD.Prefixes: %v != %v",
+					i, tt.desc, want, got)
+			}
+		} else {
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want, got := tt.prefixes, prefixes; !reflect.DeepEqual(want, got) {
+				t.Fatalf("[%02d] test %q, unexpected prefixes:\n- want: %v\n-  got: %v",
+					i, tt.desc, want, got)
+			}
+		}
+
+		status, err := iapd.Status()
+		if tt.sc == nil {
+			if want, got := dhcp6.ErrOptionNotPresent, err; want != got {
+				t.Fatalf("[%02d] test %q, unexpected error for IAPD.Status: %v != %v",
+					i, tt.desc, want, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want, got := tt.sc, status; !reflect.DeepEqual(want, got) {
+			t.Fatalf("[%02d] test %q, unexpected status:\n- want: %v\n-  got: %v",
+				i, tt.desc, want, got)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package dhcp6opts
 
 import (
+	"fmt"
 	"io"
 	"net"
 	"time"
@@ -65,7 +66,8 @@ func NewIAPrefix(preferred time.Duration, valid time.Duration, prefixLength uint
 	}
 
 	// From documentation: If ip is not an IPv4 address, To4 returns nil.
-	if prefix.To4() != nil {
+	// To16 returns nil for a nil or otherwise malformed address.
+	if prefix.To16() == nil || prefix.To4() != nil {
 		return nil, ErrInvalidIP
 	}
 
@@ -84,18 +86,27 @@ func NewIAPrefix(preferred time.Duration, valid time.Duration, prefixLength uint
 }
 
 // MarshalBinary allocates a byte slice containing the data from a IAPrefix.
+//
+// If Prefix is not an IPv6 address, such as a nil or IPv4 address,
+// ErrInvalidIP is returned.  If any option is too large to be encoded,
+// dhcp6.ErrOptionTooLarge is returned.
 func (i *IAPrefix) MarshalBinary() ([]byte, error) {
 	//  4 bytes: preferred lifetime
 	//  4 bytes: valid lifetime
 	//  1 byte : prefix length
 	// 16 bytes: IPv6 prefix
 	//  N bytes: options
+	ip := i.Prefix.To16()
+	if len(ip) != net.IPv6len || i.Prefix.To4() != nil {
+		return nil, ErrInvalidIP
+	}
+
 	b := buffer.New(nil)
 
 	b.Write32(uint32(i.PreferredLifetime / time.Second))
 	b.Write32(uint32(i.ValidLifetime / time.Second))
 	b.Write8(i.PrefixLength)
-	copy(b.WriteN(net.IPv6len), i.Prefix)
+	copy(b.WriteN(net.IPv6len), ip)
 	opts, err := i.Options.MarshalBinary()
 	if err != nil {
 		return nil, err
@@ -132,3 +143,11 @@ func (i *IAPrefix) UnmarshalBinary(p []byte) error {
 
 	return (&i.Options).UnmarshalBinary(b.Remaining())
 }
+
+// String returns a human-readable representation of an IAPrefix, such as
+// "IAPREFIX 2001:db8::/56 preferred=1h0m0s valid=infinite".  Infinite
+// lifetimes are rendered as "infinite".
+func (i *IAPrefix) String() string {
+	return fmt.Sprintf("IAPREFIX %s/%d preferred=%s valid=%s",
+		i.Prefix, i.PrefixLength, formatLifetime(i.PreferredLifetime), formatLifetime(i.ValidLifetime))
+}
@@ -24,8 +24,8 @@ func TestNewIAPrefix(t *testing.T) {
 		err       error
 	}{
 		{
-			desc:     "all zero values",
-			iaprefix: &IAPrefix{},
+			desc: "all zero values",
+			err:  ErrInvalidIP,
 		},
 		{
 			desc:      "preferred greater than valid lifetime",
@@ -5,6 +5,7 @@ import (
 	"math"
 	"net"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/mdlayher/dhcp6"
@@ -45,6 +46,57 @@ func (oro *OptionRequestOption) UnmarshalBinary(p []byte) error {
 	return nil
 }
 
+// A ConfigRequest specifies categories of configuration parameters which a
+// client requests from a server.  Its OptionRequestOption method produces
+// the Option Request Option which requests them.
+type ConfigRequest struct {
+	// DNS requests recursive DNS servers.
+	DNS bool
+
+	// Domain requests a domain search list.
+	Domain bool
+
+	// NTP requests NTP servers.
+	NTP bool
+
+	// BootFile requests a boot file URL and its parameters.
+	BootFile bool
+}
+
+// OptionRequestOption returns an OptionRequestOption containing the option
+// codes for each category requested by c, without duplicates and sorted in
+// ascending order.
+func (c ConfigRequest) OptionRequestOption() OptionRequestOption {
+	categories := []struct {
+		requested bool
+		codes     []dhcp6.OptionCode
+	}{
+		{c.DNS, []dhcp6.OptionCode{dhcp6.OptionDNSServers}},
+		{c.Domain, []dhcp6.OptionCode{dhcp6.OptionDomainList}},
+		{c.NTP, []dhcp6.OptionCode{dhcp6.OptionNTPServer}},
+		{c.BootFile, []dhcp6.OptionCode{dhcp6.OptionBootFileURL, dhcp6.OptionBootFileParam}},
+	}
+
+	seen := make(map[dhcp6.OptionCode]struct{})
+	oro := make(OptionRequestOption, 0)
+	for _, cat := range categories {
+		if !cat.requested {
+			continue
+		}
+
+		for _, code := range cat.codes {
+			if _, ok := seen[code]; ok {
+				continue
+			}
+			seen[code] = struct{}{}
+			oro = append(oro, code)
+		}
+	}
+
+	sortOptionCodes(oro)
+	return oro
+}
+
 // A Preference is a preference value, as defined in RFC 3315, Section 22.8.
 //
 // A preference value is sent by a server to a client to affect the selection
@@ -69,6 +121,57 @@ func (p *Preference) UnmarshalBinary(b []byte) error {
 	return nil
 }
 
+// A ReconfigureMessage is a Reconfigure Message option value, as defined in
+// RFC 8415, Section 21.19.
+//
+// A ReconfigureMessage is sent by a server in a Reconfigure message to
+// indicate which message type a client should use to initiate the
+// reconfiguration.  Only Renew, Rebind, and Information-request are valid.
+type ReconfigureMessage dhcp6.MessageType
+
+// MarshalBinary allocates a byte slice containing the data from a
+// ReconfigureMessage.
+//
+// If the message type is not Renew, Rebind, or Information-request,
+// ErrInvalidReconfigureMessage is returned.
+func (r ReconfigureMessage) MarshalBinary() ([]byte, error) {
+	if !r.valid() {
+		return nil, ErrInvalidReconfigureMessage
+	}
+
+	return []byte{byte(r)}, nil
+}
+
+// UnmarshalBinary unmarshals a raw byte slice into a ReconfigureMessage.
+//
+// If the byte slice is not exactly 1 byte in length, io.ErrUnexpectedEOF is
+// returned.  If the message type is not Renew, Rebind, or Information-request,
+// ErrInvalidReconfigureMessage is returned.
+func (r *ReconfigureMessage) UnmarshalBinary(b []byte) error {
+	if len(b) != 1 {
+		return io.ErrUnexpectedEOF
+	}
+
+	rm := ReconfigureMessage(b[0])
+	if !rm.valid() {
+		return ErrInvalidReconfigureMessage
+	}
+
+	*r = rm
+	return nil
+}
+
+// valid reports whether r is a message type which may be used in a
+// Reconfigure Message option.
+func (r ReconfigureMessage) valid() bool {
+	switch dhcp6.MessageType(r) {
+	case dhcp6.MessageTypeRenew, dhcp6.MessageTypeRebind, dhcp6.MessageTypeInformationRequest:
+		return true
+	}
+
+	return false
+}
+
 // An ElapsedTime is a client's elapsed request time value, as defined in RFC
 // 3315, Section 22.9.
 //
@@ -109,6 +212,42 @@ func (t *ElapsedTime) UnmarshalBinary(p []byte) error {
 	return nil
 }
 
+// An InformationRefreshTime is the upper bound on how long a client should
+// wait before refreshing information retrieved from a server, as defined in
+// RFC 4242, Section 3.
+//
+// The value is transmitted as a count of seconds.  A value of 0xffffffff
+// indicates an infinite refresh time.
+type InformationRefreshTime time.Duration
+
+// MarshalBinary allocates a byte slice containing the data from an
+// InformationRefreshTime.  Durations are truncated to whole seconds, and
+// durations too large to be represented are encoded as infinity.
+func (t InformationRefreshTime) MarshalBinary() ([]byte, error) {
+	b := buffer.New(nil)
+
+	secs := time.Duration(t) / time.Second
+	if secs > math.MaxUint32 {
+		secs = math.MaxUint32
+	}
+	b.Write32(uint32(secs))
+	return b.Data(), nil
+}
+
+// UnmarshalBinary unmarshals a raw byte slice into an InformationRefreshTime.
+//
+// If the byte slice is not exactly 4 bytes in length, io.ErrUnexpectedEOF is
+// returned.
+func (t *InformationRefreshTime) UnmarshalBinary(p []byte) error {
+	b := buffer.New(p)
+	if b.Len() != 4 {
+		return io.ErrUnexpectedEOF
+	}
+
+	*t = InformationRefreshTime(time.Duration(b.Read32()) * time.Second)
+	return nil
+}
+
 // An IP is an IPv6 address.  The IP type is provided for convenience.
 // It can be used to easily add IPv6 addresses to an Options map.
 type IP net.IP
@@ -138,14 +277,26 @@ func (i *IP) UnmarshalBinary(b []byte) error {
 	return nil
 }
 
-// IPs represents a list of IPv6 addresses.
+// IPs represents a list of IPv6 addresses of servers, such as DNS recursive
+// name servers.
 type IPs []net.IP
 
 // MarshalBinary allocates a byte slice containing the consecutive data of all
 // IPs.
+//
+// If any IP is not an IPv6 address, ErrInvalidIP is returned.  If any IP is
+// unspecified or a multicast address, and therefore cannot be used to contact
+// a server, ErrInvalidServerIP is returned.
 func (i IPs) MarshalBinary() ([]byte, error) {
 	ips := make([]byte, 0, len(i)*net.IPv6len)
 	for _, ip := range i {
+		if ip.To16() == nil || ip.To4() != nil {
+			return nil, ErrInvalidIP
+		}
+		if ip.IsUnspecified() || ip.IsMulticast() {
+			return nil, ErrInvalidServerIP
+		}
+
 		ips = append(ips, ip.To16()...)
 	}
 	return ips, nil
@@ -170,6 +321,184 @@ func (i *IPs) UnmarshalBinary(p []byte) error {
 	return nil
 }
 
+// A DomainSearchList is a list of domain names which a client should use
+// when resolving hostnames with DNS, as described in RFC 3646, Section 4.
+type DomainSearchList []string
+
+// MarshalBinary allocates a byte slice containing the data from a
+// DomainSearchList, encoding each domain name as described in RFC 1035,
+// Section 3.1.
+//
+// If any domain name contains an empty label, a label longer than 63 bytes,
+// or is longer than 255 bytes once encoded, ErrInvalidDomainName is returned.
+func (d DomainSearchList) MarshalBinary() ([]byte, error) {
+	b := buffer.New(nil)
+	for _, name := range d {
+		// A trailing dot indicates a fully qualified name, and does not
+		// add an extra label.
+		name = strings.TrimSuffix(name, ".")
+
+		// 1 byte: length of the terminating root label
+		n := 1
+		for _, label := range strings.Split(name, ".") {
+			if len(label) == 0 || len(label) > maxLabelLen {
+				return nil, ErrInvalidDomainName
+			}
+
+			n += 1 + len(label)
+			if n > maxNameLen {
+				return nil, ErrInvalidDomainName
+			}
+
+			// 1 byte: label length
+			// N bytes: label
+			b.Write8(uint8(len(label)))
+			b.WriteBytes([]byte(label))
+		}
+
+		b.Write8(0)
+	}
+
+	return b.Data(), nil
+}
+
+// UnmarshalBinary unmarshals a raw byte slice into a DomainSearchList.
+// Domain names are returned without a trailing dot.
+//
+// If the byte slice is empty or ends in the middle of a domain name,
+// io.ErrUnexpectedEOF is returned.  If a domain name contains a label
+// longer than 63 bytes, or is longer than 255 bytes, ErrInvalidDomainName is
+// returned.  Compressed domain names are not permitted, as described in
+// RFC 3646, Section 4.
+func (d *DomainSearchList) UnmarshalBinary(p []byte) error {
+	// At least one domain name must be present.
+	if len(p) == 0 {
+		return io.ErrUnexpectedEOF
+	}
+
+	b := buffer.New(p)
+
+	*d = make(DomainSearchList, 0)
+	for b.Len() > 0 {
+		var labels []string
+
+		// 1 byte: length of the terminating root label
+		n := 1
+		for {
+			if b.Len() == 0 {
+				return io.ErrUnexpectedEOF
+			}
+
+			l := int(b.Read8())
+			if l == 0 {
+				break
+			}
+			if l > maxLabelLen {
+				return ErrInvalidDomainName
+			}
+
+			n += 1 + l
+			if n > maxNameLen {
+				return ErrInvalidDomainName
+			}
+
+			label := b.Consume(l)
+			if label == nil {
+				return io.ErrUnexpectedEOF
+			}
+			labels = append(labels, string(label))
+		}
+
+		// The root domain alone is not a useful search domain.
+		if len(labels) == 0 {
+			return ErrInvalidDomainName
+		}
+
+		*d = append(*d, strings.Join(labels, "."))
+	}
+
+	return nil
+}
+
+const (
+	// maxLabelLen and maxNameLen are the maximum lengths of a domain name
+	// label and an encoded domain name, as described in RFC 1035,
+	// Section 2.3.4.
+	maxLabelLen = 63
+	maxNameLen  = 255
+)
+
+// NTP Server option suboption codes, as described in RFC 5908, Section 4.
+const (
+	ntpSuboptionSrvAddr = 1
+	ntpSuboptionMCAddr  = 2
+)
+
+// NTPServers represents a list of NTP server IPv6 addresses, carried in the
+// NTP Server Option, as described in RFC 5908, Section 4.
+type NTPServers []net.IP
+
+// MarshalBinary allocates a byte slice containing the data from NTPServers,
+// encoding each address as an NTP server address suboption.
+//
+// If any address is not an IPv6 address, ErrInvalidIP is returned.
+func (n NTPServers) MarshalBinary() ([]byte, error) {
+	b := buffer.New(nil)
+	for _, ip := range n {
+		if ip.To16() == nil || ip.To4() != nil {
+			return nil, ErrInvalidIP
+		}
+
+		// 2 bytes: suboption code
+		// 2 bytes: suboption length
+		// 16 bytes: IPv6 address
+		b.Write16(ntpSuboptionSrvAddr)
+		b.Write16(net.IPv6len)
+		b.WriteBytes(ip.To16())
+	}
+	return b.Data(), nil
+}
+
+// UnmarshalBinary unmarshals a raw byte slice into NTPServers.  Addresses
+// from both NTP server address and NTP multicast address suboptions are
+// returned in order.  NTP server FQDN suboptions are ignored.
+//
+// If the byte slice contains no suboptions, a truncated suboption, or an
+// address suboption which is not an IPv6 address, io.ErrUnexpectedEOF is
+// returned.
+func (n *NTPServers) UnmarshalBinary(p []byte) error {
+	b := buffer.New(p)
+	if b.Len() == 0 {
+		return io.ErrUnexpectedEOF
+	}
+
+	*n = make(NTPServers, 0)
+	for b.Len() > 0 {
+		if b.Len() < 4 {
+			return io.ErrUnexpectedEOF
+		}
+
+		code := b.Read16()
+		v := b.Consume(int(b.Read16()))
+		if v == nil {
+			return io.ErrUnexpectedEOF
+		}
+
+		switch code {
+		case ntpSuboptionSrvAddr, ntpSuboptionMCAddr:
+			if len(v) != net.IPv6len {
+				return io.ErrUnexpectedEOF
+			}
+
+			ip := make(net.IP, net.IPv6len)
+			copy(ip, v)
+			*n = append(*n, ip)
+		}
+	}
+
+	return nil
+}
+
 // Data is a raw collection of byte slices, typically carrying user class
 // data, vendor class data, or PXE boot file parameters.
 type Data [][]byte
@@ -200,7 +529,15 @@ func (d Data) Marshal(b *buffer.Buffer) {
 }
 
 // UnmarshalBinary unmarshals a raw byte slice into a Data structure.
+//
+// If the byte slice is empty, d is set to an empty, non-nil Data, because
+// options such as User Class may be present but carry no data.
 func (d *Data) UnmarshalBinary(p []byte) error {
+	if len(p) == 0 {
+		*d = Data{}
+		return nil
+	}
+
 	b := buffer.New(p)
 	return d.Unmarshal(b)
 }
@@ -348,7 +685,9 @@ type RelayMessageOption []byte
 
 // MarshalBinary allocates a byte slice containing the data from a RelayMessageOption.
 func (r *RelayMessageOption) MarshalBinary() ([]byte, error) {
-	return *r, nil
+	b := make([]byte, len(*r))
+	copy(b, *r)
+	return b, nil
 }
 
 // UnmarshalBinary unmarshals a raw byte slice into a RelayMessageOption.
@@ -409,9 +748,19 @@ func (r *RelayMessageOption) RelayMessage() (*RelayMessage, error) {
 // relay agent's interfaces.
 type InterfaceID []byte
 
+// NewInterfaceID creates a new InterfaceID from a copy of the opaque data
+// which identifies a relay agent's interface.  An InterfaceID can be added
+// to the Options map of a Relay-forward message using Options.Add, and is
+// echoed back unmodified by a server in its Relay-reply message.
+func NewInterfaceID(data []byte) InterfaceID {
+	id := make(InterfaceID, len(data))
+	copy(id, data)
+	return id
+}
+
 // MarshalBinary allocates a byte slice containing the data from a InterfaceID.
-func (i *InterfaceID) MarshalBinary() ([]byte, error) {
-	return *i, nil
+func (i InterfaceID) MarshalBinary() ([]byte, error) {
+	return i, nil
 }
 
 // UnmarshalBinary unmarshals a raw byte slice into a InterfaceID.
@@ -424,6 +773,21 @@ func (i *InterfaceID) UnmarshalBinary(b []byte) error {
 // A BootFileParam are boot file parameters.
 type BootFileParam []string
 
+// NewBootFileParam creates a new BootFileParam from an ordered list of boot
+// file parameters, such as kernel command line arguments.
+func NewBootFileParam(params ...string) BootFileParam {
+	bfp := make(BootFileParam, len(params))
+	copy(bfp, params)
+	return bfp
+}
+
+// Params returns a copy of the ordered boot file parameters as strings.
+func (bfp BootFileParam) Params() []string {
+	params := make([]string, len(bfp))
+	copy(params, bfp)
+	return params
+}
+
 // MarshalBinary allocates a byte slice containing the data from a
 // BootFileParam.
 func (bfp BootFileParam) MarshalBinary() ([]byte, error) {
@@ -437,6 +801,11 @@ func (bfp BootFileParam) MarshalBinary() ([]byte, error) {
 
 // UnmarshalBinary unmarshals a raw byte slice into a BootFileParam.
 func (bfp *BootFileParam) UnmarshalBinary(b []byte) error {
+	// At least one parameter must be present
+	if len(b) == 0 {
+		return io.ErrUnexpectedEOF
+	}
+
 	var d Data
 	if err := (&d).UnmarshalBinary(b); err != nil {
 		return err
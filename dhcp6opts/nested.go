@@ -0,0 +1,96 @@
+package dhcp6opts
+
+import (
+	"github.com/mdlayher/dhcp6"
+)
+
+// GetNested retrieves the value of an option encapsulated within other
+// options, by descending through each OptionCode in path in turn.  For
+// example, the path OptionIANA, OptionIAAddr, OptionStatusCode retrieves
+// the Status Code option of the first address in the first IANA.
+//
+// Each option in path except the last must encapsulate options: IANA, IATA,
+// IAPD, IAAddr, IAPrefix, RSOO, or a Relay Message containing a client
+// message or another relay message.  If more than one value is present for
+// an option in path, the first value is used.
+//
+// GetNested is intended for tests and diagnostics.  If path is empty or any
+// option in path is not present, dhcp6.ErrOptionNotPresent is returned.  If
+// an option other than the last in path does not encapsulate options,
+// ErrNotEncapsulating is returned.  If an encapsulating option is malformed,
+// its error is returned.
+func GetNested(o dhcp6.Options, path ...dhcp6.OptionCode) ([]byte, error) {
+	if len(path) == 0 {
+		return nil, dhcp6.ErrOptionNotPresent
+	}
+
+	// Descend through each encapsulating option, then retrieve the value
+	// of the final option in path.
+	last := len(path) - 1
+	for _, code := range path[:last] {
+		vv, err := o.Get(code)
+		if err != nil {
+			return nil, err
+		}
+
+		o, err = encapsulatedOptions(code, vv[0])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	vv, err := o.Get(path[last])
+	if err != nil {
+		return nil, err
+	}
+
+	return vv[0], nil
+}
+
+// encapsulatedOptions returns the options encapsulated within the value v
+// of the option specified by code.
+func encapsulatedOptions(code dhcp6.OptionCode, v []byte) (dhcp6.Options, error) {
+	switch code {
+	case dhcp6.OptionIANA:
+		ia := new(IANA)
+		err := ia.UnmarshalBinary(v)
+		return ia.Options, err
+	case dhcp6.OptionIATA:
+		ia := new(IATA)
+		err := ia.UnmarshalBinary(v)
+		return ia.Options, err
+	case dhcp6.OptionIAPD:
+		ia := new(IAPD)
+		err := ia.UnmarshalBinary(v)
+		return ia.Options, err
+	case dhcp6.OptionIAAddr:
+		iaa := new(IAAddr)
+		err := iaa.UnmarshalBinary(v)
+		return iaa.Options, err
+	case dhcp6.OptionIAPrefix:
+		iap := new(IAPrefix)
+		err := iap.UnmarshalBinary(v)
+		return iap.Options, err
+	case dhcp6.OptionRSOO:
+		var o dhcp6.Options
+		err := o.UnmarshalBinary(v)
+		return o, err
+	case dhcp6.OptionRelayMsg:
+		if len(v) == 0 {
+			return nil, dhcp6.ErrInvalidPacket
+		}
+
+		switch dhcp6.MessageType(v[0]) {
+		case dhcp6.MessageTypeRelayForw, dhcp6.MessageTypeRelayRepl:
+			rm := new(RelayMessage)
+			err := rm.UnmarshalBinary(v)
+			return rm.Options, err
+		default:
+			p := new(dhcp6.Packet)
+			err := p.UnmarshalBinary(v)
+			return p.Options, err
+		}
+	}
+
+	return nil, ErrNotEncapsulating
+}
@@ -0,0 +1,125 @@
+package dhcp6opts
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/mdlayher/dhcp6"
+)
+
+// TestGetNested verifies that GetNested descends through encapsulated
+// options following a path of option codes.
+func TestGetNested(t *testing.T) {
+	status := NewStatusCode(dhcp6.StatusNoBinding, "no binding")
+	sb, err := status.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	iaaOpts := make(dhcp6.Options)
+	if err := iaaOpts.Add(dhcp6.OptionStatusCode, status); err != nil {
+		t.Fatal(err)
+	}
+
+	iaa, err := NewIAAddr(net.ParseIP("2001:db8::1"), 30*time.Second, 60*time.Second, iaaOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	iaOpts := make(dhcp6.Options)
+	if err := iaOpts.Add(dhcp6.OptionIAAddr, iaa); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := make(dhcp6.Options)
+	if err := opts.Add(dhcp6.OptionIANA, NewIANA([4]byte{0, 1, 2, 3}, 0, 0, iaOpts)); err != nil {
+		t.Fatal(err)
+	}
+
+	inner := &dhcp6.Packet{
+		MessageType:   dhcp6.MessageTypeRenew,
+		TransactionID: [3]byte{0, 1, 2},
+		Options:       opts,
+	}
+
+	rm, err := NewRelayForward(net.ParseIP("2001:db8::ff"), net.ParseIP("fe80::1"), inner)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	relayOpts := make(dhcp6.Options)
+	if err := relayOpts.Add(dhcp6.OptionRelayMsg, rm); err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = []struct {
+		desc    string
+		options dhcp6.Options
+		path    []dhcp6.OptionCode
+		b       []byte
+		err     error
+	}{
+		{
+			desc:    "empty path",
+			options: opts,
+			err:     dhcp6.ErrOptionNotPresent,
+		},
+		{
+			desc:    "IANA not present",
+			options: dhcp6.Options{},
+			path:    []dhcp6.OptionCode{dhcp6.OptionIANA, dhcp6.OptionIAAddr},
+			err:     dhcp6.ErrOptionNotPresent,
+		},
+		{
+			desc:    "Status Code not present in IANA",
+			options: opts,
+			path:    []dhcp6.OptionCode{dhcp6.OptionIANA, dhcp6.OptionStatusCode},
+			err:     dhcp6.ErrOptionNotPresent,
+		},
+		{
+			desc: "Preference does not encapsulate options",
+			options: dhcp6.Options{
+				dhcp6.OptionPreference: [][]byte{{255}},
+			},
+			path: []dhcp6.OptionCode{dhcp6.OptionPreference, dhcp6.OptionStatusCode},
+			err:  ErrNotEncapsulating,
+		},
+		{
+			desc: "malformed IANA",
+			options: dhcp6.Options{
+				dhcp6.OptionIANA: [][]byte{{0}},
+			},
+			path: []dhcp6.OptionCode{dhcp6.OptionIANA, dhcp6.OptionIAAddr},
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc:    "IANA, IAAddr, Status Code",
+			options: opts,
+			path:    []dhcp6.OptionCode{dhcp6.OptionIANA, dhcp6.OptionIAAddr, dhcp6.OptionStatusCode},
+			b:       sb,
+		},
+		{
+			desc:    "Relay-forward, Renew, IANA, IAAddr, Status Code",
+			options: relayOpts,
+			path:    []dhcp6.OptionCode{dhcp6.OptionRelayMsg, dhcp6.OptionRelayMsg, dhcp6.OptionIANA, dhcp6.OptionIAAddr, dhcp6.OptionStatusCode},
+			b:       sb,
+		},
+	}
+
+	for i, tt := range tests {
+		b, err := GetNested(tt.options, tt.path...)
+		if want, got := tt.err, err; want != got {
+			t.Errorf("[%02d] test %q, unexpected error for GetNested: %v != %v",
+				i, tt.desc, want, got)
+			continue
+		}
+
+		if want, got := tt.b, b; !bytes.Equal(want, got) {
+			t.Errorf("[%02d] test %q, unexpected value for GetNested:\n- want: %v\n-  got: %v",
+				i, tt.desc, want, got)
+		}
+	}
+}
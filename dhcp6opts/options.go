@@ -1,6 +1,8 @@
 package dhcp6opts
 
 import (
+	"net"
+
 	"github.com/mdlayher/dhcp6"
 )
 
@@ -51,6 +53,25 @@ func GetIANA(o dhcp6.Options) ([]*IANA, error) {
 	return iana, nil
 }
 
+// GetIANAByIAID returns the Identity Association for Non-temporary Addresses
+// Option value with the specified IAID, as described in RFC 3315,
+// Section 22.4.
+//
+// If no IANA with the IAID is present, dhcp6.ErrOptionNotPresent is returned.
+func GetIANAByIAID(o dhcp6.Options, iaid [4]byte) (*IANA, error) {
+	ias, err := GetIANA(o)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ia := range ias {
+		if ia.IAID == iaid {
+			return ia, nil
+		}
+	}
+	return nil, dhcp6.ErrOptionNotPresent
+}
+
 // GetIATA returns the Identity Association for Temporary Addresses Option
 // value, as described in RFC 3315, Section 22.5.
 //
@@ -72,6 +93,25 @@ func GetIATA(o dhcp6.Options) ([]*IATA, error) {
 	return iata, nil
 }
 
+// GetIATAByIAID returns the Identity Association for Temporary Addresses
+// Option value with the specified IAID, as described in RFC 3315,
+// Section 22.5.
+//
+// If no IATA with the IAID is present, dhcp6.ErrOptionNotPresent is returned.
+func GetIATAByIAID(o dhcp6.Options, iaid [4]byte) (*IATA, error) {
+	ias, err := GetIATA(o)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ia := range ias {
+		if ia.IAID == iaid {
+			return ia, nil
+		}
+	}
+	return nil, dhcp6.ErrOptionNotPresent
+}
+
 // GetIAAddr returns the Identity Association Address Option value, as described
 // in RFC 3315, Section 22.6.
 //
@@ -94,6 +134,48 @@ func GetIAAddr(o dhcp6.Options) ([]*IAAddr, error) {
 	return iaAddr, nil
 }
 
+// GetAllIAAddrs returns the Identity Association Address Option values
+// encapsulated in every IANA and IATA option value, as described in RFC 3315,
+// Sections 22.4 through 22.6.  The IAAddr values of IANAs are returned before
+// those of IATAs, each in the order in which they appear.
+//
+// IANA and IATA values which contain no IAAddr values are skipped.  If no
+// IAAddr values are present, an empty slice and nil are returned.  If any
+// IANA, IATA, or IAAddr value cannot be parsed, its error is returned.
+func GetAllIAAddrs(o dhcp6.Options) ([]*IAAddr, error) {
+	ianas, err := GetIANA(o)
+	if err != nil && err != dhcp6.ErrOptionNotPresent {
+		return nil, err
+	}
+	iatas, err := GetIATA(o)
+	if err != nil && err != dhcp6.ErrOptionNotPresent {
+		return nil, err
+	}
+
+	opts := make([]dhcp6.Options, 0, len(ianas)+len(iatas))
+	for _, ia := range ianas {
+		opts = append(opts, ia.Options)
+	}
+	for _, ia := range iatas {
+		opts = append(opts, ia.Options)
+	}
+
+	addrs := make([]*IAAddr, 0)
+	for _, o := range opts {
+		as, err := GetIAAddr(o)
+		switch err {
+		case nil:
+			addrs = append(addrs, as...)
+		case dhcp6.ErrOptionNotPresent:
+			// No addresses in this IA.
+		default:
+			return nil, err
+		}
+	}
+
+	return addrs, nil
+}
+
 // GetOptionRequest returns the Option Request Option value, as described in
 // RFC 3315, Section 22.7.
 //
@@ -142,6 +224,22 @@ func GetElapsedTime(o dhcp6.Options) (ElapsedTime, error) {
 	return t, err
 }
 
+// GetInformationRefreshTime returns the Information Refresh Time Option
+// value, as described in RFC 4242, Section 3.
+//
+// The information refresh time is the upper bound on how long a client
+// should wait before refreshing information retrieved from a server.
+func GetInformationRefreshTime(o dhcp6.Options) (InformationRefreshTime, error) {
+	v, err := o.GetOne(dhcp6.OptionInformationRefreshTime)
+	if err != nil {
+		return 0, err
+	}
+
+	var t InformationRefreshTime
+	err = t.UnmarshalBinary(v)
+	return t, err
+}
+
 // GetRelayMessageOption returns the Relay Message Option value, as described
 // in RFC 3315, Section 22.10.
 //
@@ -206,6 +304,28 @@ func GetStatusCode(o dhcp6.Options) (*StatusCode, error) {
 	return s, err
 }
 
+// GetStatusCodes returns all Status Code Option values, described in RFC 3315,
+// Section 22.13.
+//
+// At most one Status Code option should appear in a given Options map, and
+// GetStatusCode should be used in most cases.  GetStatusCodes is useful for
+// diagnosing messages which carry more than one Status Code option.
+func GetStatusCodes(o dhcp6.Options) ([]*StatusCode, error) {
+	vv, err := o.Get(dhcp6.OptionStatusCode)
+	if err != nil {
+		return nil, err
+	}
+
+	sc := make([]*StatusCode, len(vv))
+	for i := range vv {
+		sc[i] = new(StatusCode)
+		if err := sc[i].UnmarshalBinary(vv[i]); err != nil {
+			return nil, err
+		}
+	}
+	return sc, nil
+}
+
 // GetRapidCommit returns the Rapid Commit Option value, described in RFC 3315,
 // Section 22.14.
 //
@@ -224,11 +344,70 @@ func GetRapidCommit(o dhcp6.Options) error {
 	return nil
 }
 
+// GetReconfigureAccept returns the Reconfigure Accept Option value, described
+// in RFC 3315, Section 22.20.  A client includes this option to indicate
+// that it is willing to accept Reconfigure messages from a server.
+//
+// Nil is returned if OptionReconfAccept was present in the Options map.
+func GetReconfigureAccept(o dhcp6.Options) error {
+	v, err := o.GetOne(dhcp6.OptionReconfAccept)
+	if err != nil {
+		return err
+	}
+
+	// Data must be completely empty; presence of the Reconfigure Accept
+	// option indicates Reconfigure messages are accepted.
+	if len(v) != 0 {
+		return dhcp6.ErrInvalidPacket
+	}
+	return nil
+}
+
+// GetReconfigureMessage returns the Reconfigure Message Option value,
+// described in RFC 8415, Section 21.19.  A server includes this option in a
+// Reconfigure message to indicate which message type a client should send in
+// response.
+//
+// The ReconfigureMessage is returned only if it is Renew, Rebind, or
+// Information-request.
+func GetReconfigureMessage(o dhcp6.Options) (ReconfigureMessage, error) {
+	v, err := o.GetOne(dhcp6.OptionReconfMsg)
+	if err != nil {
+		return 0, err
+	}
+
+	var r ReconfigureMessage
+	err = r.UnmarshalBinary(v)
+	return r, err
+}
+
+// GetAddrRegEnable returns the Address Registration option value, described
+// in RFC 9686, Section 4.1.  A server includes this option to indicate that
+// clients may register self-generated addresses using the ADDR-REG-INFORM
+// message.
+//
+// Nil is returned if OptionAddrRegEnable was present in the Options map.
+func GetAddrRegEnable(o dhcp6.Options) error {
+	v, err := o.GetOne(dhcp6.OptionAddrRegEnable)
+	if err != nil {
+		return err
+	}
+
+	// Data must be completely empty; presence of the Address Registration
+	// option indicates address registration is supported.
+	if len(v) != 0 {
+		return dhcp6.ErrInvalidPacket
+	}
+	return nil
+}
+
 // GetUserClass returns the User Class Option value, described in RFC 3315,
 // Section 22.15.
 //
 // The Data structure returned contains any raw class data present in
-// the option.
+// the option.  If the option is present but carries no data, an empty,
+// non-nil Data is returned, distinguishing it from an absent option, for
+// which dhcp6.ErrOptionNotPresent is returned.
 func GetUserClass(o dhcp6.Options) (Data, error) {
 	v, err := o.GetOne(dhcp6.OptionUserClass)
 	if err != nil {
@@ -310,6 +489,24 @@ func GetIAPD(o dhcp6.Options) ([]*IAPD, error) {
 	return iapd, nil
 }
 
+// GetIAPDByIAID returns the Identity Association for Prefix Delegation Option
+// value with the specified IAID, as described in RFC 3633, Section 9.
+//
+// If no IAPD with the IAID is present, dhcp6.ErrOptionNotPresent is returned.
+func GetIAPDByIAID(o dhcp6.Options, iaid [4]byte) (*IAPD, error) {
+	ias, err := GetIAPD(o)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ia := range ias {
+		if ia.IAID == iaid {
+			return ia, nil
+		}
+	}
+	return nil, dhcp6.ErrOptionNotPresent
+}
+
 // GetIAPrefix returns the Identity Association Prefix Option value, as
 // described in RFC 3633, Section 10.
 //
@@ -332,6 +529,35 @@ func GetIAPrefix(o dhcp6.Options) ([]*IAPrefix, error) {
 	return iaPrefix, nil
 }
 
+// GetAllIAPrefixes returns the Identity Association Prefix Option values
+// encapsulated in every IAPD option value, as described in RFC 3633,
+// Sections 9 and 10, in the order in which they appear.
+//
+// IAPD values which contain no IAPrefix values are skipped.  If no IAPrefix
+// values are present, an empty slice and nil are returned.  If any IAPD or
+// IAPrefix value cannot be parsed, its error is returned.
+func GetAllIAPrefixes(o dhcp6.Options) ([]*IAPrefix, error) {
+	iapds, err := GetIAPD(o)
+	if err != nil && err != dhcp6.ErrOptionNotPresent {
+		return nil, err
+	}
+
+	prefixes := make([]*IAPrefix, 0)
+	for _, ia := range iapds {
+		ps, err := ia.Prefixes()
+		switch err {
+		case nil:
+			prefixes = append(prefixes, ps...)
+		case dhcp6.ErrOptionNotPresent:
+			// No prefixes in this IAPD.
+		default:
+			return nil, err
+		}
+	}
+
+	return prefixes, nil
+}
+
 // GetRemoteIdentifier returns the Remote Identifier, described in RFC 4649.
 //
 // This option may be added by DHCPv6 relay agents that terminate
@@ -428,3 +654,102 @@ func GetDNSServers(o dhcp6.Options) (IPs, error) {
 	err = ips.UnmarshalBinary(v)
 	return ips, err
 }
+
+// SetDNSServers sets the DNS Recursive Name Servers Option value, as
+// described in RFC 3646, Section 3, replacing any existing value.  The DNS
+// servers should be listed in the order of preference for use by the client
+// resolver.
+//
+// If any IP is not a usable IPv6 server address, an error is returned and o
+// is not modified.
+func SetDNSServers(o dhcp6.Options, ips []net.IP) error {
+	return o.Set(dhcp6.OptionDNSServers, IPs(ips))
+}
+
+// GetSNTPServers returns the Simple Network Time Protocol (SNTP) Servers
+// Option value, as described in RFC 4075, Section 4.
+//
+// The SNTP servers are listed in the order of preference for use by the
+// client.
+func GetSNTPServers(o dhcp6.Options) (IPs, error) {
+	v, err := o.GetOne(dhcp6.OptionSNTPServers)
+	if err != nil {
+		return nil, err
+	}
+
+	var ips IPs
+	err = ips.UnmarshalBinary(v)
+	return ips, err
+}
+
+// SetSNTPServers sets the Simple Network Time Protocol (SNTP) Servers Option
+// value, as described in RFC 4075, Section 4, replacing any existing value.
+// The SNTP servers should be listed in the order of preference for use by
+// the client.
+//
+// If any IP is not a usable IPv6 server address, an error is returned and o
+// is not modified.
+func SetSNTPServers(o dhcp6.Options, ips []net.IP) error {
+	return o.Set(dhcp6.OptionSNTPServers, IPs(ips))
+}
+
+// GetDomainSearchList returns the Domain Search List Option value, as
+// described in RFC 3646, Section 4.
+//
+// The domain names are listed in the order of preference for use by the
+// client resolver.
+func GetDomainSearchList(o dhcp6.Options) (DomainSearchList, error) {
+	v, err := o.GetOne(dhcp6.OptionDomainList)
+	if err != nil {
+		return nil, err
+	}
+
+	var d DomainSearchList
+	err = d.UnmarshalBinary(v)
+	return d, err
+}
+
+// GetNTPServers returns the NTP Server Option value, as described in
+// RFC 5908, Section 4.
+//
+// The NTP servers are listed in the order of preference for use by the
+// client.
+func GetNTPServers(o dhcp6.Options) (NTPServers, error) {
+	v, err := o.GetOne(dhcp6.OptionNTPServer)
+	if err != nil {
+		return nil, err
+	}
+
+	var n NTPServers
+	err = n.UnmarshalBinary(v)
+	return n, err
+}
+
+// GetRelaySuppliedOptions returns the Relay-Supplied Options Option value,
+// described in RFC 6422, Section 3.
+//
+// The Options map returned contains options supplied by a relay agent, which
+// a server may include in its response to a client.  The RSOO option must
+// only appear in the Options map of a Relay-forward message.  Relay agents
+// may add an RSOO option using SetRelaySuppliedOptions.
+func GetRelaySuppliedOptions(o dhcp6.Options) (dhcp6.Options, error) {
+	v, err := o.GetOne(dhcp6.OptionRSOO)
+	if err != nil {
+		return nil, err
+	}
+
+	var rsoo dhcp6.Options
+	err = (&rsoo).UnmarshalBinary(v)
+	return rsoo, err
+}
+
+// SetRelaySuppliedOptions sets the Relay-Supplied Options Option value,
+// described in RFC 6422, Section 3, replacing any existing value.  A relay
+// agent uses this option to supply options which a server may include in
+// its response to a client, and sets it only in the Options map of a
+// Relay-forward message.
+//
+// If rsoo cannot be marshaled, an error is returned and o is not modified.
+func SetRelaySuppliedOptions(o dhcp6.Options, rsoo dhcp6.Options) error {
+	return o.Set(dhcp6.OptionRSOO, rsoo)
+}
@@ -8,6 +8,7 @@ import (
 	"net"
 	"net/url"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -713,6 +714,56 @@ func TestGetOptionRequest(t *testing.T) {
 	}
 }
 
+// TestConfigRequestOptionRequestOption verifies that
+// ConfigRequest.OptionRequestOption produces the option codes for each
+// requested category, in ascending order.
+func TestConfigRequestOptionRequestOption(t *testing.T) {
+	var tests = []struct {
+		desc string
+		c    ConfigRequest
+		oro  OptionRequestOption
+	}{
+		{
+			desc: "nothing requested",
+			oro:  OptionRequestOption{},
+		},
+		{
+			desc: "DNS and NTP",
+			c: ConfigRequest{
+				NTP: true,
+				DNS: true,
+			},
+			oro: OptionRequestOption{
+				dhcp6.OptionDNSServers,
+				dhcp6.OptionNTPServer,
+			},
+		},
+		{
+			desc: "all categories",
+			c: ConfigRequest{
+				DNS:      true,
+				Domain:   true,
+				NTP:      true,
+				BootFile: true,
+			},
+			oro: OptionRequestOption{
+				dhcp6.OptionDNSServers,
+				dhcp6.OptionDomainList,
+				dhcp6.OptionNTPServer,
+				dhcp6.OptionBootFileURL,
+				dhcp6.OptionBootFileParam,
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		if want, got := tt.oro, tt.c.OptionRequestOption(); !reflect.DeepEqual(want, got) {
+			t.Fatalf("[%02d] test %q, unexpected OptionRequestOption:\n- want: %v\n-  got: %v",
+				i, tt.desc, want, got)
+		}
+	}
+}
+
 // TestGetPreference verifies that dhcp6.Options.Preference properly parses
 // and returns an integer value, if it is available with OptionPreference.
 func TestGetPreference(t *testing.T) {
@@ -884,6 +935,75 @@ func TestGetStatusCode(t *testing.T) {
 	}
 }
 
+// TestGetStatusCodes verifies that GetStatusCodes properly parses and returns
+// all StatusCode values, if they are available with OptionStatusCode.
+func TestGetStatusCodes(t *testing.T) {
+	var tests = []struct {
+		desc    string
+		options dhcp6.Options
+		sc      []*StatusCode
+		err     error
+	}{
+		{
+			desc: "OptionStatusCode not present in dhcp6.Options map",
+			err:  dhcp6.ErrOptionNotPresent,
+		},
+		{
+			desc: "OptionStatusCode present in dhcp6.Options map, but too short length",
+			options: dhcp6.Options{
+				dhcp6.OptionStatusCode: [][]byte{{0, 0}, {}},
+			},
+			err: io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "One OptionStatusCode present in dhcp6.Options map",
+			options: dhcp6.Options{
+				dhcp6.OptionStatusCode: [][]byte{{0, 0}},
+			},
+			sc: []*StatusCode{
+				{
+					Code: dhcp6.StatusSuccess,
+				},
+			},
+		},
+		{
+			desc: "Two OptionStatusCode present in dhcp6.Options map",
+			options: dhcp6.Options{
+				dhcp6.OptionStatusCode: [][]byte{
+					append([]byte{0, 0}, []byte("deadbeef")...),
+					append([]byte{0, 2}, []byte("no addresses")...),
+				},
+			},
+			sc: []*StatusCode{
+				{
+					Code:    dhcp6.StatusSuccess,
+					Message: "deadbeef",
+				},
+				{
+					Code:    dhcp6.StatusNoAddrsAvail,
+					Message: "no addresses",
+				},
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		sc, err := GetStatusCodes(tt.options)
+		if err != nil {
+			if want, got := tt.err, err; want != got {
+				t.Errorf("[%02d] test %q, unexpected error for GetStatusCodes(dhcp6.Options): %v != %v",
+					i, tt.desc, want, got)
+			}
+			continue
+		}
+
+		if want, got := tt.sc, sc; !reflect.DeepEqual(want, got) {
+			t.Errorf("[%02d] test %q, unexpected value for GetStatusCodes(dhcp6.Options):\n- want: %v\n-  got: %v",
+				i, tt.desc, want, got)
+		}
+	}
+}
+
 // TestGetElapsedTime verifies that dhcp6.Options.ElapsedTime properly parses and
 // returns a time.Duration value, if one is available with OptionElapsedTime.
 func TestGetElapsedTime(t *testing.T) {
@@ -985,6 +1105,102 @@ func TestElapsedTimeMarshalBinary(t *testing.T) {
 	}
 }
 
+// TestGetInformationRefreshTime verifies that GetInformationRefreshTime
+// properly parses and returns an InformationRefreshTime, if one is available
+// with OptionInformationRefreshTime.
+func TestGetInformationRefreshTime(t *testing.T) {
+	var tests = []struct {
+		desc     string
+		options  dhcp6.Options
+		duration InformationRefreshTime
+		err      error
+	}{
+		{
+			desc: "OptionInformationRefreshTime not present in dhcp6.Options map",
+			err:  dhcp6.ErrOptionNotPresent,
+		},
+		{
+			desc: "OptionInformationRefreshTime present in dhcp6.Options map, but too short",
+			options: dhcp6.Options{
+				dhcp6.OptionInformationRefreshTime: [][]byte{{0, 0, 1}},
+			},
+			err: io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "OptionInformationRefreshTime present in dhcp6.Options map, but too long",
+			options: dhcp6.Options{
+				dhcp6.OptionInformationRefreshTime: [][]byte{{0, 0, 0, 1, 0}},
+			},
+			err: io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "OptionInformationRefreshTime present in dhcp6.Options map",
+			options: dhcp6.Options{
+				dhcp6.OptionInformationRefreshTime: [][]byte{{0, 1, 0x51, 0x80}},
+			},
+			duration: InformationRefreshTime(24 * time.Hour),
+		},
+	}
+
+	for i, tt := range tests {
+		duration, err := GetInformationRefreshTime(tt.options)
+		if err != nil {
+			if want, got := tt.err, err; want != got {
+				t.Errorf("[%02d] test %q, unexpected error for GetInformationRefreshTime(dhcp6.Options): %v != %v",
+					i, tt.desc, want, got)
+			}
+			continue
+		}
+
+		if want, got := tt.duration, duration; want != got {
+			t.Errorf("[%02d] test %q, unexpected value for GetInformationRefreshTime(dhcp6.Options): %v != %v",
+				i, tt.desc, want, got)
+		}
+	}
+}
+
+// TestInformationRefreshTimeMarshalBinary verifies that InformationRefreshTime
+// properly marshals into a byte slice.
+func TestInformationRefreshTimeMarshalBinary(t *testing.T) {
+	var tests = []struct {
+		desc     string
+		duration InformationRefreshTime
+		buf      []byte
+	}{
+		{
+			desc: "zero",
+			buf:  []byte{0, 0, 0, 0},
+		},
+		{
+			desc:     "sub-second precision truncated",
+			duration: InformationRefreshTime(600*time.Second + 900*time.Millisecond),
+			buf:      []byte{0, 0, 0x02, 0x58},
+		},
+		{
+			desc:     "largest finite value",
+			duration: InformationRefreshTime((1<<32 - 2) * time.Second),
+			buf:      []byte{0xff, 0xff, 0xff, 0xfe},
+		},
+		{
+			desc:     "too large, encoded as infinity",
+			duration: InformationRefreshTime((1<<32 + 1) * time.Second),
+			buf:      []byte{0xff, 0xff, 0xff, 0xff},
+		},
+	}
+
+	for i, tt := range tests {
+		buf, err := tt.duration.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if want, got := tt.buf, buf; !bytes.Equal(want, got) {
+			t.Errorf("[%02d] test %q, unexpected InformationRefreshTime bytes\n- want: %v\n-  got: %v",
+				i, tt.desc, want, got)
+		}
+	}
+}
+
 // TestGetRelayMessage verifies that dhcp6.Options.RelayMessageOption properly parses and
 // returns an relay message option value, if one is available with RelayMessageOption.
 func TestGetRelayMessage(t *testing.T) {
@@ -1118,6 +1334,192 @@ func TestGetRapidCommit(t *testing.T) {
 	}
 }
 
+// TestGetReconfigureAccept verifies that dhcp6.Options.ReconfigureAccept
+// properly parses and returns a nil error if OptionReconfAccept is present.
+func TestGetReconfigureAccept(t *testing.T) {
+	var tests = []struct {
+		desc    string
+		options dhcp6.Options
+		err     error
+	}{
+		{
+			desc: "OptionReconfAccept not present in dhcp6.Options map",
+			err:  dhcp6.ErrOptionNotPresent,
+		},
+		{
+			desc: "OptionReconfAccept present in dhcp6.Options map, but non-empty",
+			options: dhcp6.Options{
+				dhcp6.OptionReconfAccept: [][]byte{{1}},
+			},
+			err: dhcp6.ErrInvalidPacket,
+		},
+		{
+			desc: "OptionReconfAccept present in dhcp6.Options map, empty",
+			options: dhcp6.Options{
+				dhcp6.OptionReconfAccept: [][]byte{},
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		err := GetReconfigureAccept(tt.options)
+		if want, got := tt.err, err; want != got {
+			t.Errorf("[%02d] test %q, unexpected error for dhcp6.Options.ReconfigureAccept: %v != %v",
+				i, tt.desc, want, got)
+		}
+	}
+}
+
+// TestGetReconfigureMessage verifies that GetReconfigureMessage properly
+// parses and returns a ReconfigureMessage, and rejects message types which
+// are not permitted in a Reconfigure Message option.
+func TestGetReconfigureMessage(t *testing.T) {
+	var tests = []struct {
+		desc    string
+		options dhcp6.Options
+		r       ReconfigureMessage
+		err     error
+	}{
+		{
+			desc: "OptionReconfMsg not present in dhcp6.Options map",
+			err:  dhcp6.ErrOptionNotPresent,
+		},
+		{
+			desc: "OptionReconfMsg present in dhcp6.Options map, but empty",
+			options: dhcp6.Options{
+				dhcp6.OptionReconfMsg: [][]byte{{}},
+			},
+			err: io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "OptionReconfMsg present in dhcp6.Options map, but too long",
+			options: dhcp6.Options{
+				dhcp6.OptionReconfMsg: [][]byte{{5, 5}},
+			},
+			err: io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "OptionReconfMsg present in dhcp6.Options map, but Solicit",
+			options: dhcp6.Options{
+				dhcp6.OptionReconfMsg: [][]byte{{byte(dhcp6.MessageTypeSolicit)}},
+			},
+			err: ErrInvalidReconfigureMessage,
+		},
+		{
+			desc: "OptionReconfMsg present in dhcp6.Options map, Renew",
+			options: dhcp6.Options{
+				dhcp6.OptionReconfMsg: [][]byte{{byte(dhcp6.MessageTypeRenew)}},
+			},
+			r: ReconfigureMessage(dhcp6.MessageTypeRenew),
+		},
+		{
+			desc: "OptionReconfMsg present in dhcp6.Options map, Rebind",
+			options: dhcp6.Options{
+				dhcp6.OptionReconfMsg: [][]byte{{byte(dhcp6.MessageTypeRebind)}},
+			},
+			r: ReconfigureMessage(dhcp6.MessageTypeRebind),
+		},
+		{
+			desc: "OptionReconfMsg present in dhcp6.Options map, Information-request",
+			options: dhcp6.Options{
+				dhcp6.OptionReconfMsg: [][]byte{{byte(dhcp6.MessageTypeInformationRequest)}},
+			},
+			r: ReconfigureMessage(dhcp6.MessageTypeInformationRequest),
+		},
+	}
+
+	for i, tt := range tests {
+		r, err := GetReconfigureMessage(tt.options)
+		if want, got := tt.err, err; want != got {
+			t.Errorf("[%02d] test %q, unexpected error for GetReconfigureMessage: %v != %v",
+				i, tt.desc, want, got)
+			continue
+		}
+
+		if want, got := tt.r, r; want != got {
+			t.Errorf("[%02d] test %q, unexpected value for GetReconfigureMessage: %v != %v",
+				i, tt.desc, want, got)
+		}
+	}
+}
+
+// TestReconfigureMessageMarshalBinary verifies that ReconfigureMessage
+// only marshals message types permitted in a Reconfigure Message option.
+func TestReconfigureMessageMarshalBinary(t *testing.T) {
+	var tests = []struct {
+		desc string
+		r    ReconfigureMessage
+		b    []byte
+		err  error
+	}{
+		{
+			desc: "Renew",
+			r:    ReconfigureMessage(dhcp6.MessageTypeRenew),
+			b:    []byte{5},
+		},
+		{
+			desc: "Information-request",
+			r:    ReconfigureMessage(dhcp6.MessageTypeInformationRequest),
+			b:    []byte{11},
+		},
+		{
+			desc: "Reply",
+			r:    ReconfigureMessage(dhcp6.MessageTypeReply),
+			err:  ErrInvalidReconfigureMessage,
+		},
+	}
+
+	for i, tt := range tests {
+		b, err := tt.r.MarshalBinary()
+		if want, got := tt.err, err; want != got {
+			t.Errorf("[%02d] test %q, unexpected error for ReconfigureMessage.MarshalBinary: %v != %v",
+				i, tt.desc, want, got)
+			continue
+		}
+
+		if want, got := tt.b, b; !bytes.Equal(want, got) {
+			t.Errorf("[%02d] test %q, unexpected bytes for ReconfigureMessage.MarshalBinary: %v != %v",
+				i, tt.desc, want, got)
+		}
+	}
+}
+
+// TestGetAddrRegEnable verifies that dhcp6.Options.AddrRegEnable properly
+// parses and returns a nil error if OptionAddrRegEnable is present.
+func TestGetAddrRegEnable(t *testing.T) {
+	var tests = []struct {
+		desc    string
+		options dhcp6.Options
+		err     error
+	}{
+		{
+			desc: "OptionAddrRegEnable not present in dhcp6.Options map",
+			err:  dhcp6.ErrOptionNotPresent,
+		},
+		{
+			desc: "OptionAddrRegEnable present in dhcp6.Options map, but non-empty",
+			options: dhcp6.Options{
+				dhcp6.OptionAddrRegEnable: [][]byte{{1}},
+			},
+			err: dhcp6.ErrInvalidPacket,
+		},
+		{
+			desc: "OptionAddrRegEnable present in dhcp6.Options map, empty",
+			options: dhcp6.Options{
+				dhcp6.OptionAddrRegEnable: [][]byte{},
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		err := GetAddrRegEnable(tt.options)
+		if want, got := tt.err, err; want != got {
+			t.Errorf("[%02d] test %q, unexpected error for dhcp6.Options.AddrRegEnable: %v != %v",
+				i, tt.desc, want, got)
+		}
+	}
+}
+
 // TestGetUserClass verifies that dhcp6.Options.UserClass properly parses
 // and returns raw user class data, if it is available with OptionUserClass.
 func TestGetUserClass(t *testing.T) {
@@ -1136,7 +1538,7 @@ func TestGetUserClass(t *testing.T) {
 			options: dhcp6.Options{
 				dhcp6.OptionUserClass: [][]byte{{}},
 			},
-			err: io.ErrUnexpectedEOF,
+			classes: [][]byte{},
 		},
 		{
 			desc: "OptionUserClass present in dhcp6.Options map, one item, zero length",
@@ -1203,13 +1605,56 @@ func TestGetUserClass(t *testing.T) {
 	}
 }
 
-// TestGetVendorClass verifies that dhcp6.Options.VendorClass properly parses
-// and returns raw vendor class data, if it is available with OptionVendorClass.
-func TestGetVendorClass(t *testing.T) {
+// TestGetUserClassPresence verifies that GetUserClass distinguishes an absent
+// User Class option from one which is present but carries no data.
+func TestGetUserClassPresence(t *testing.T) {
 	var tests = []struct {
 		desc    string
 		options dhcp6.Options
-		classes [][]byte
+		classes Data
+		err     error
+	}{
+		{
+			desc: "absent",
+			err:  dhcp6.ErrOptionNotPresent,
+		},
+		{
+			desc: "present, empty",
+			options: dhcp6.Options{
+				dhcp6.OptionUserClass: [][]byte{{}},
+			},
+			classes: Data{},
+		},
+		{
+			desc: "present, with data",
+			options: dhcp6.Options{
+				dhcp6.OptionUserClass: [][]byte{{0, 1, 1}},
+			},
+			classes: Data{{1}},
+		},
+	}
+
+	for i, tt := range tests {
+		classes, err := GetUserClass(tt.options)
+		if want, got := tt.err, err; want != got {
+			t.Fatalf("[%02d] test %q, unexpected error for GetUserClass(dhcp6.Options): %v != %v",
+				i, tt.desc, want, got)
+		}
+
+		if want, got := tt.classes, classes; !reflect.DeepEqual(want, got) {
+			t.Fatalf("[%02d] test %q, unexpected value for GetUserClass(dhcp6.Options): %#v != %#v",
+				i, tt.desc, want, got)
+		}
+	}
+}
+
+// TestGetVendorClass verifies that dhcp6.Options.VendorClass properly parses
+// and returns raw vendor class data, if it is available with OptionVendorClass.
+func TestGetVendorClass(t *testing.T) {
+	var tests = []struct {
+		desc    string
+		options dhcp6.Options
+		classes [][]byte
 		err     error
 	}{
 		{
@@ -1349,6 +1794,41 @@ func TestInterfaceID(t *testing.T) {
 	}
 }
 
+// TestNewInterfaceID verifies that an InterfaceID created by NewInterfaceID
+// can be added to an Options map, and round-trips its opaque data exactly.
+func TestNewInterfaceID(t *testing.T) {
+	data := []byte{0x00, 0xff, 'e', 't', 'h', '0', 0x80}
+	id := NewInterfaceID(data)
+
+	// The input data is copied.
+	data[0] = 0x01
+
+	o := make(dhcp6.Options)
+	if err := o.Add(dhcp6.OptionInterfaceID, id); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := o.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got dhcp6.Options
+	if err := got.UnmarshalBinary(b); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := GetInterfaceID(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []byte{0x00, 0xff, 'e', 't', 'h', '0', 0x80}
+	if !bytes.Equal(want, out) {
+		t.Fatalf("unexpected InterfaceID:\n- want: %v\n-  got: %v", want, out)
+	}
+}
+
 // TestGetIAPD verifies that dhcp6.Options.IAPD properly parses and
 // returns multiple IAPD values, if one or more are available with OptionIAPD.
 func TestGetIAPD(t *testing.T) {
@@ -1437,6 +1917,124 @@ func TestGetIAPD(t *testing.T) {
 	}
 }
 
+// TestGetAllIAAddrs verifies that GetAllIAAddrs returns the IAAddr values
+// from every IANA and IATA, skipping those which contain no addresses.
+func TestGetAllIAAddrs(t *testing.T) {
+	newAddr := func(ip string) *IAAddr {
+		a, err := NewIAAddr(net.ParseIP(ip), time.Hour, 2*time.Hour, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return a
+	}
+
+	o := make(dhcp6.Options)
+
+	// No IAs present.
+	addrs, err := GetAllIAAddrs(o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(addrs) != 0 {
+		t.Fatalf("unexpected IAAddrs: %v", addrs)
+	}
+
+	withAddrs := NewIANA([4]byte{1}, 0, 0, nil)
+	if err := withAddrs.SetAddresses(newAddr("2001:db8::1"), newAddr("2001:db8::2")); err != nil {
+		t.Fatal(err)
+	}
+	noAddrs := NewIANA([4]byte{2}, 0, 0, nil)
+	iata := NewIATA([4]byte{3}, nil)
+	if err := iata.Options.Add(dhcp6.OptionIAAddr, newAddr("2001:db8::3")); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, ia := range []*IANA{withAddrs, noAddrs} {
+		if err := o.Add(dhcp6.OptionIANA, ia); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := o.Add(dhcp6.OptionIATA, iata); err != nil {
+		t.Fatal(err)
+	}
+
+	addrs, err = GetAllIAAddrs(o)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []net.IP{
+		net.ParseIP("2001:db8::1"),
+		net.ParseIP("2001:db8::2"),
+		net.ParseIP("2001:db8::3"),
+	}
+	if len(want) != len(addrs) {
+		t.Fatalf("unexpected number of IAAddrs: %v != %v", len(want), len(addrs))
+	}
+	for i := range want {
+		if !want[i].Equal(addrs[i].IP) {
+			t.Fatalf("[%02d] unexpected IAAddr IP: %v != %v", i, want[i], addrs[i].IP)
+		}
+	}
+
+	// A malformed IAAddr is reported.
+	bad := NewIANA([4]byte{4}, 0, 0, nil)
+	bad.Options.AddRaw(dhcp6.OptionIAAddr, []byte{1})
+	if err := o.Add(dhcp6.OptionIANA, bad); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := GetAllIAAddrs(o); err != io.ErrUnexpectedEOF {
+		t.Fatalf("unexpected error for malformed IAAddr: %v", err)
+	}
+}
+
+// TestGetAllIAPrefixes verifies that GetAllIAPrefixes returns the IAPrefix
+// values from every IAPD, skipping those which contain no prefixes.
+func TestGetAllIAPrefixes(t *testing.T) {
+	newPrefix := func(ip string) *IAPrefix {
+		p, err := NewIAPrefix(time.Hour, 2*time.Hour, 56, net.ParseIP(ip), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+
+	withPrefix := NewIAPD([4]byte{1}, 0, 0, nil)
+	if err := withPrefix.Options.Add(dhcp6.OptionIAPrefix, newPrefix("2001:db8:1::")); err != nil {
+		t.Fatal(err)
+	}
+	noPrefix := NewIAPD([4]byte{2}, 0, 0, nil)
+	another := NewIAPD([4]byte{3}, 0, 0, nil)
+	if err := another.Options.Add(dhcp6.OptionIAPrefix, newPrefix("2001:db8:2::")); err != nil {
+		t.Fatal(err)
+	}
+
+	o := make(dhcp6.Options)
+	for _, ia := range []*IAPD{withPrefix, noPrefix, another} {
+		if err := o.Add(dhcp6.OptionIAPD, ia); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	prefixes, err := GetAllIAPrefixes(o)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []net.IP{
+		net.ParseIP("2001:db8:1::"),
+		net.ParseIP("2001:db8:2::"),
+	}
+	if len(want) != len(prefixes) {
+		t.Fatalf("unexpected number of IAPrefixes: %v != %v", len(want), len(prefixes))
+	}
+	for i := range want {
+		if !want[i].Equal(prefixes[i].Prefix) {
+			t.Fatalf("[%02d] unexpected IAPrefix: %v != %v", i, want[i], prefixes[i].Prefix)
+		}
+	}
+}
+
 // TestGetIAPrefix verifies that dhcp6.Options.IAPrefix properly parses and
 // returns multiple IAPrefix values, if one or more are available with
 // OptionIAPrefix.
@@ -1714,6 +2312,27 @@ func TestGetBootFileParam(t *testing.T) {
 	}
 }
 
+// TestBootFileParamRoundTrip verifies that a BootFileParam created with
+// NewBootFileParam can be added to and retrieved from dhcp6.Options, and
+// that its parameters are returned in order.
+func TestBootFileParamRoundTrip(t *testing.T) {
+	params := []string{"console=ttyS0", "root=/dev/nfs"}
+
+	o := make(dhcp6.Options)
+	if err := o.Add(dhcp6.OptionBootFileParam, NewBootFileParam(params...)); err != nil {
+		t.Fatal(err)
+	}
+
+	bfp, err := GetBootFileParam(o)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := params, bfp.Params(); !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected boot file parameters:\n- want: %v\n-  got: %v", want, got)
+	}
+}
+
 // TestGetClientArchType verifies that dhcp6.Options.ClientArchType properly parses
 // and returns client architecture type data, if it is available with
 // OptionClientArchType.
@@ -1907,3 +2526,575 @@ func TestGetDNSServers(t *testing.T) {
 		}
 	}
 }
+
+// TestGetSNTPServers verifies that GetSNTPServers returns a valid list of
+// SNTP server IPv6 addresses, or an appropriate error.
+func TestGetSNTPServers(t *testing.T) {
+	var tests = []struct {
+		desc    string
+		options dhcp6.Options
+		sntp    IPs
+		err     error
+	}{
+		{
+			desc: "OptionSNTPServers not present in dhcp6.Options map",
+			err:  dhcp6.ErrOptionNotPresent,
+		},
+		{
+			desc: "OptionSNTPServers present in dhcp6.Options map, but empty",
+			options: dhcp6.Options{
+				dhcp6.OptionSNTPServers: [][]byte{{}},
+			},
+			err: io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "OptionSNTPServers present in dhcp6.Options map, but not a multiple of 16 bytes",
+			options: dhcp6.Options{
+				dhcp6.OptionSNTPServers: [][]byte{bytes.Repeat([]byte{0xff}, 17)},
+			},
+			err: io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "Two OptionSNTPServers present in dhcp6.Options map",
+			options: dhcp6.Options{
+				dhcp6.OptionSNTPServers: [][]byte{append(bytes.Repeat([]byte{0xfd}, 16), bytes.Repeat([]byte{0xfc}, 16)...)},
+			},
+			sntp: IPs{
+				net.IP(bytes.Repeat([]byte{0xfd}, 16)),
+				net.IP(bytes.Repeat([]byte{0xfc}, 16)),
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		sntp, err := GetSNTPServers(tt.options)
+		if err != nil {
+			if want, got := tt.err, err; want != got {
+				t.Errorf("[%02d] test %q, unexpected error for GetSNTPServers(dhcp6.Options): %v != %v",
+					i, tt.desc, want, got)
+			}
+			continue
+		}
+
+		if want, got := tt.sntp, sntp; !reflect.DeepEqual(want, got) {
+			t.Errorf("[%02d] test %q, unexpected value for GetSNTPServers(dhcp6.Options): %v != %v",
+				i, tt.desc, want, got)
+		}
+	}
+}
+
+// TestSetSNTPServers verifies that SetSNTPServers replaces any existing
+// SNTP Servers option, and rejects unusable server addresses.
+func TestSetSNTPServers(t *testing.T) {
+	o := dhcp6.Options{
+		dhcp6.OptionSNTPServers: [][]byte{{0}, {1}},
+	}
+
+	ips := []net.IP{net.ParseIP("2001:db8::1"), net.ParseIP("2001:db8::2")}
+	if err := SetSNTPServers(o, ips); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := GetSNTPServers(o)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := IPs(ips); !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected SNTP servers: %v != %v", want, got)
+	}
+
+	if want, got := ErrInvalidIP, SetSNTPServers(o, []net.IP{net.IPv4(192, 0, 2, 1)}); want != got {
+		t.Fatalf("unexpected error for IPv4 address: %v != %v", want, got)
+	}
+}
+
+// TestSetDNSServers verifies that SetDNSServers only accepts usable IPv6
+// DNS server addresses.
+func TestSetDNSServers(t *testing.T) {
+	var tests = []struct {
+		desc string
+		ips  []net.IP
+		err  error
+	}{
+		{
+			desc: "IPv4 address",
+			ips:  []net.IP{net.IPv4(192, 0, 2, 53)},
+			err:  ErrInvalidIP,
+		},
+		{
+			desc: "unspecified address",
+			ips:  []net.IP{net.IPv6unspecified},
+			err:  ErrInvalidServerIP,
+		},
+		{
+			desc: "multicast address",
+			ips:  []net.IP{net.ParseIP("ff02::1")},
+			err:  ErrInvalidServerIP,
+		},
+		{
+			desc: "global address after multicast address",
+			ips:  []net.IP{net.ParseIP("ff02::1"), net.ParseIP("2001:db8::53")},
+			err:  ErrInvalidServerIP,
+		},
+		{
+			desc: "global address",
+			ips:  []net.IP{net.ParseIP("2001:db8::53")},
+		},
+		{
+			desc: "two global addresses",
+			ips:  []net.IP{net.ParseIP("2001:db8::53"), net.ParseIP("2001:db8::5353")},
+		},
+	}
+
+	for i, tt := range tests {
+		// Any existing value is replaced.
+		o := dhcp6.Options{
+			dhcp6.OptionDNSServers: [][]byte{make([]byte, 16)},
+		}
+
+		if err := SetDNSServers(o, tt.ips); err != nil {
+			if want, got := tt.err, err; want != got {
+				t.Fatalf("[%02d] test %q, unexpected error for SetDNSServers: %v != %v",
+					i, tt.desc, want, got)
+			}
+
+			// Options must not be modified on error.
+			if want, got := [][]byte{make([]byte, 16)}, o[dhcp6.OptionDNSServers]; !reflect.DeepEqual(want, got) {
+				t.Fatalf("[%02d] test %q, unexpected DNS servers after error: %v != %v",
+					i, tt.desc, want, got)
+			}
+			continue
+		}
+
+		dns, err := GetDNSServers(o)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want, got := IPs(tt.ips), dns; !reflect.DeepEqual(want, got) {
+			t.Fatalf("[%02d] test %q, unexpected DNS servers: %v != %v",
+				i, tt.desc, want, got)
+		}
+	}
+}
+
+// TestGetNTPServers verifies that GetNTPServers properly parses and returns
+// a list of net.IPs, if it is available with OptionNTPServer.
+func TestGetNTPServers(t *testing.T) {
+	srv := net.ParseIP("2001:db8::123")
+	mc := net.ParseIP("ff05::101")
+
+	var tests = []struct {
+		desc    string
+		options dhcp6.Options
+		ntp     NTPServers
+		err     error
+	}{
+		{
+			desc: "OptionNTPServer not present in dhcp6.Options map",
+			err:  dhcp6.ErrOptionNotPresent,
+		},
+		{
+			desc: "OptionNTPServer present in dhcp6.Options map, but empty",
+			options: dhcp6.Options{
+				dhcp6.OptionNTPServer: [][]byte{{}},
+			},
+			err: io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "OptionNTPServer present in dhcp6.Options map, but truncated suboption",
+			options: dhcp6.Options{
+				dhcp6.OptionNTPServer: [][]byte{{0, 1, 0, 16, 0xff}},
+			},
+			err: io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "OptionNTPServer present in dhcp6.Options map, but address too short",
+			options: dhcp6.Options{
+				dhcp6.OptionNTPServer: [][]byte{{0, 1, 0, 1, 0xff}},
+			},
+			err: io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "OptionNTPServer present in dhcp6.Options map, server, multicast, and FQDN suboptions",
+			options: dhcp6.Options{
+				dhcp6.OptionNTPServer: [][]byte{bytes.Join([][]byte{
+					{0, 1, 0, 16}, srv,
+					{0, 3, 0, 5, 3, 'n', 't', 'p', 0},
+					{0, 2, 0, 16}, mc,
+				}, nil)},
+			},
+			ntp: NTPServers{srv, mc},
+		},
+	}
+
+	for i, tt := range tests {
+		ntp, err := GetNTPServers(tt.options)
+		if err != nil {
+			if want, got := tt.err, err; want != got {
+				t.Errorf("[%02d] test %q, unexpected error for GetNTPServers(dhcp6.Options): %v != %v",
+					i, tt.desc, want, got)
+			}
+			continue
+		}
+
+		if want, got := tt.ntp, ntp; !reflect.DeepEqual(want, got) {
+			t.Errorf("[%02d] test %q, unexpected value for GetNTPServers(dhcp6.Options): %v != %v",
+				i, tt.desc, want, got)
+		}
+	}
+}
+
+// TestNTPServersMarshalBinary verifies that NTPServers encodes each address
+// as an NTP server address suboption.
+func TestNTPServersMarshalBinary(t *testing.T) {
+	srv := net.ParseIP("2001:db8::123")
+
+	b, err := NTPServers{srv, srv}.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := bytes.Join([][]byte{{0, 1, 0, 16}, srv, {0, 1, 0, 16}, srv}, nil)
+	if got := b; !bytes.Equal(want, got) {
+		t.Fatalf("unexpected NTPServers bytes:\n- want: %v\n-  got: %v", want, got)
+	}
+}
+
+// TestNTPServersMarshalBinaryInvalidIP verifies that NTPServers returns
+// ErrInvalidIP for addresses which are not IPv6 addresses.
+func TestNTPServersMarshalBinaryInvalidIP(t *testing.T) {
+	var tests = []struct {
+		desc string
+		ip   net.IP
+	}{
+		{
+			desc: "nil IP",
+		},
+		{
+			desc: "invalid IP",
+			ip:   net.IP{1, 2, 3},
+		},
+		{
+			desc: "IPv4 IP",
+			ip:   net.IPv4(192, 0, 2, 1),
+		},
+	}
+
+	for i, tt := range tests {
+		srvs := NTPServers{net.ParseIP("2001:db8::123"), tt.ip}
+		if _, err := srvs.MarshalBinary(); err != ErrInvalidIP {
+			t.Errorf("[%02d] test %q, unexpected error for NTPServers.MarshalBinary: %v != %v",
+				i, tt.desc, ErrInvalidIP, err)
+		}
+	}
+}
+
+// TestDomainSearchListBinary verifies that DomainSearchList values are
+// encoded and decoded using RFC 1035 domain name encoding.
+func TestDomainSearchListBinary(t *testing.T) {
+	var tests = []struct {
+		desc    string
+		domains DomainSearchList
+		b       []byte
+		out     DomainSearchList
+	}{
+		{
+			desc:    "one domain",
+			domains: DomainSearchList{"example.com"},
+			b:       []byte{7, 'e', 'x', 'a', 'm', 'p', 'l', 'e', 3, 'c', 'o', 'm', 0},
+		},
+		{
+			desc:    "fully qualified domain",
+			domains: DomainSearchList{"example.com."},
+			b:       []byte{7, 'e', 'x', 'a', 'm', 'p', 'l', 'e', 3, 'c', 'o', 'm', 0},
+			out:     DomainSearchList{"example.com"},
+		},
+		{
+			desc:    "two domains",
+			domains: DomainSearchList{"a.example", "b"},
+			b:       []byte{1, 'a', 7, 'e', 'x', 'a', 'm', 'p', 'l', 'e', 0, 1, 'b', 0},
+		},
+	}
+
+	for i, tt := range tests {
+		b, err := tt.domains.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if want, got := tt.b, b; !bytes.Equal(want, got) {
+			t.Fatalf("[%02d] test %q, unexpected DomainSearchList bytes:\n- want: %v\n-  got: %v",
+				i, tt.desc, want, got)
+		}
+
+		var d DomainSearchList
+		if err := d.UnmarshalBinary(b); err != nil {
+			t.Fatal(err)
+		}
+
+		want := tt.out
+		if want == nil {
+			want = tt.domains
+		}
+		if got := d; !reflect.DeepEqual(want, got) {
+			t.Fatalf("[%02d] test %q, unexpected DomainSearchList:\n- want: %v\n-  got: %v",
+				i, tt.desc, want, got)
+		}
+	}
+}
+
+// TestDomainSearchListBinaryErrors verifies that invalid domain names are
+// rejected when encoding and decoding a DomainSearchList.
+func TestDomainSearchListBinaryErrors(t *testing.T) {
+	longLabel := strings.Repeat("a", 64)
+	longName := strings.TrimSuffix(strings.Repeat(strings.Repeat("a", 63)+".", 4), ".")
+
+	var marshalTests = []struct {
+		desc    string
+		domains DomainSearchList
+	}{
+		{desc: "empty name", domains: DomainSearchList{""}},
+		{desc: "empty label", domains: DomainSearchList{"a..com"}},
+		{desc: "label too long", domains: DomainSearchList{longLabel + ".com"}},
+		{desc: "name too long", domains: DomainSearchList{longName}},
+	}
+
+	for i, tt := range marshalTests {
+		if _, err := tt.domains.MarshalBinary(); err != ErrInvalidDomainName {
+			t.Fatalf("[%02d] test %q, unexpected error: %v != %v",
+				i, tt.desc, ErrInvalidDomainName, err)
+		}
+	}
+
+	var unmarshalTests = []struct {
+		desc string
+		b    []byte
+		err  error
+	}{
+		{desc: "missing terminator", b: []byte{1, 'a'}, err: io.ErrUnexpectedEOF},
+		{desc: "truncated label", b: []byte{3, 'a'}, err: io.ErrUnexpectedEOF},
+		{desc: "root domain only", b: []byte{0}, err: ErrInvalidDomainName},
+		{desc: "compression pointer", b: []byte{0xc0, 0x0c}, err: ErrInvalidDomainName},
+	}
+
+	for i, tt := range unmarshalTests {
+		var d DomainSearchList
+		if want, got := tt.err, d.UnmarshalBinary(tt.b); want != got {
+			t.Fatalf("[%02d] test %q, unexpected error: %v != %v",
+				i, tt.desc, want, got)
+		}
+	}
+}
+
+// TestGetDomainSearchList verifies that GetDomainSearchList properly parses
+// and returns a DomainSearchList, if it is available with OptionDomainList.
+func TestGetDomainSearchList(t *testing.T) {
+	var tests = []struct {
+		desc    string
+		options dhcp6.Options
+		domains DomainSearchList
+		err     error
+	}{
+		{
+			desc: "OptionDomainList not present in dhcp6.Options map",
+			err:  dhcp6.ErrOptionNotPresent,
+		},
+		{
+			desc: "OptionDomainList present in dhcp6.Options map, but empty",
+			options: dhcp6.Options{
+				dhcp6.OptionDomainList: [][]byte{{}},
+			},
+			err: io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "OptionDomainList present in dhcp6.Options map, but label too long",
+			options: dhcp6.Options{
+				dhcp6.OptionDomainList: [][]byte{append(append([]byte{64}, bytes.Repeat([]byte{'a'}, 64)...), 0)},
+			},
+			err: ErrInvalidDomainName,
+		},
+		{
+			desc: "OptionDomainList present in dhcp6.Options map, two domains",
+			options: dhcp6.Options{
+				dhcp6.OptionDomainList: [][]byte{{
+					7, 'e', 'x', 'a', 'm', 'p', 'l', 'e', 3, 'c', 'o', 'm', 0,
+					3, 'l', 'a', 'n', 0,
+				}},
+			},
+			domains: DomainSearchList{"example.com", "lan"},
+		},
+	}
+
+	for i, tt := range tests {
+		domains, err := GetDomainSearchList(tt.options)
+		if err != nil {
+			if want, got := tt.err, err; want != got {
+				t.Errorf("[%02d] test %q, unexpected error for GetDomainSearchList(dhcp6.Options): %v != %v",
+					i, tt.desc, want, got)
+			}
+			continue
+		}
+
+		if want, got := tt.domains, domains; !reflect.DeepEqual(want, got) {
+			t.Errorf("[%02d] test %q, unexpected value for GetDomainSearchList(dhcp6.Options): %v != %v",
+				i, tt.desc, want, got)
+		}
+	}
+}
+
+// TestGetRelaySuppliedOptions verifies that GetRelaySuppliedOptions properly
+// parses and returns an Options map, if it is available with OptionRSOO.
+func TestGetRelaySuppliedOptions(t *testing.T) {
+	dns := IPs{net.ParseIP("2001:db8::53")}
+
+	nested := make(dhcp6.Options)
+	if err := nested.Add(dhcp6.OptionDNSServers, dns); err != nil {
+		t.Fatal(err)
+	}
+
+	o := make(dhcp6.Options)
+	if err := o.Add(dhcp6.OptionRSOO, nested); err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = []struct {
+		desc    string
+		options dhcp6.Options
+		rsoo    dhcp6.Options
+		err     error
+	}{
+		{
+			desc: "OptionRSOO not present in dhcp6.Options map",
+			err:  dhcp6.ErrOptionNotPresent,
+		},
+		{
+			desc: "OptionRSOO present in dhcp6.Options map, but malformed",
+			options: dhcp6.Options{
+				dhcp6.OptionRSOO: [][]byte{{0, 23, 0, 16}},
+			},
+			err: dhcp6.ErrInvalidOptions,
+		},
+		{
+			desc:    "OptionRSOO present in dhcp6.Options map, with nested DNS servers",
+			options: o,
+			rsoo:    nested,
+		},
+	}
+
+	for i, tt := range tests {
+		rsoo, err := GetRelaySuppliedOptions(tt.options)
+		if want, got := tt.err, err; want != got {
+			t.Errorf("[%02d] test %q, unexpected error for GetRelaySuppliedOptions(dhcp6.Options): %v != %v",
+				i, tt.desc, want, got)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+
+		if want, got := tt.rsoo, rsoo; !reflect.DeepEqual(want, got) {
+			t.Errorf("[%02d] test %q, unexpected value for GetRelaySuppliedOptions(dhcp6.Options):\n- want: %v\n-  got: %v",
+				i, tt.desc, want, got)
+		}
+
+		got, err := GetDNSServers(rsoo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := dns; !reflect.DeepEqual(want, got) {
+			t.Errorf("[%02d] test %q, unexpected nested DNS servers:\n- want: %v\n-  got: %v",
+				i, tt.desc, want, got)
+		}
+	}
+}
+
+// TestSetRelaySuppliedOptions verifies that SetRelaySuppliedOptions replaces
+// any existing RSOO value, and that nested options round-trip through
+// GetRelaySuppliedOptions.
+func TestSetRelaySuppliedOptions(t *testing.T) {
+	dns := IPs{net.ParseIP("2001:db8::53")}
+
+	nested := make(dhcp6.Options)
+	if err := SetDNSServers(nested, dns); err != nil {
+		t.Fatal(err)
+	}
+
+	o := dhcp6.Options{
+		dhcp6.OptionRSOO: [][]byte{{0, 7, 0, 1, 255}},
+	}
+	if err := SetRelaySuppliedOptions(o, nested); err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := 1, len(o[dhcp6.OptionRSOO]); want != got {
+		t.Fatalf("unexpected number of RSOO values: %v != %v", want, got)
+	}
+
+	rsoo, err := GetRelaySuppliedOptions(o)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := GetDNSServers(rsoo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := dns; !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected nested DNS servers:\n- want: %v\n-  got: %v", want, got)
+	}
+}
+
+// TestGetIAByIAID verifies that GetIANAByIAID, GetIATAByIAID, and
+// GetIAPDByIAID select the identity association with a matching IAID.
+func TestGetIAByIAID(t *testing.T) {
+	first := [4]byte{0, 0, 0, 1}
+	second := [4]byte{0, 0, 0, 2}
+	missing := [4]byte{0, 0, 0, 3}
+
+	o := make(dhcp6.Options)
+	for _, iaid := range [][4]byte{first, second} {
+		o.Add(dhcp6.OptionIANA, NewIANA(iaid, 0, 0, nil))
+		o.Add(dhcp6.OptionIATA, NewIATA(iaid, nil))
+		o.Add(dhcp6.OptionIAPD, NewIAPD(iaid, 0, 0, nil))
+	}
+
+	for _, iaid := range [][4]byte{first, second} {
+		iana, err := GetIANAByIAID(o, iaid)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want, got := iaid, iana.IAID; want != got {
+			t.Fatalf("unexpected IANA IAID: %v != %v", want, got)
+		}
+
+		iata, err := GetIATAByIAID(o, iaid)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want, got := iaid, iata.IAID; want != got {
+			t.Fatalf("unexpected IATA IAID: %v != %v", want, got)
+		}
+
+		iapd, err := GetIAPDByIAID(o, iaid)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want, got := iaid, iapd.IAID; want != got {
+			t.Fatalf("unexpected IAPD IAID: %v != %v", want, got)
+		}
+	}
+
+	if _, err := GetIANAByIAID(o, missing); err != dhcp6.ErrOptionNotPresent {
+		t.Fatalf("unexpected error for missing IANA: %v != %v", dhcp6.ErrOptionNotPresent, err)
+	}
+	if _, err := GetIATAByIAID(o, missing); err != dhcp6.ErrOptionNotPresent {
+		t.Fatalf("unexpected error for missing IATA: %v != %v", dhcp6.ErrOptionNotPresent, err)
+	}
+	if _, err := GetIAPDByIAID(o, missing); err != dhcp6.ErrOptionNotPresent {
+		t.Fatalf("unexpected error for missing IAPD: %v != %v", dhcp6.ErrOptionNotPresent, err)
+	}
+	if _, err := GetIANAByIAID(nil, first); err != dhcp6.ErrOptionNotPresent {
+		t.Fatalf("unexpected error for empty options: %v != %v", dhcp6.ErrOptionNotPresent, err)
+	}
+}
@@ -7,22 +7,68 @@ import (
 //go:generate stringer -output=string.go -type=ArchType,DUIDType
 
 var (
+	// ErrAuthFailed is returned by AuthVerify when the HMAC in a message's
+	// Authentication option does not match the message.
+	ErrAuthFailed = errors.New("authentication failed")
+
 	// ErrHardwareTypeNotImplemented is returned when HardwareType is not
 	// implemented on the current platform.
 	ErrHardwareTypeNotImplemented = errors.New("hardware type detection not implemented on this platform")
 
+	// ErrHopCountLimit is returned by WrapForward when a Relay-forward
+	// message has already been relayed HopCountLimit times.
+	ErrHopCountLimit = errors.New("relay message hop count limit reached")
+
+	// ErrInvalidDomainName is returned when a domain name cannot be encoded
+	// or decoded using the format described in RFC 1035, Section 3.1.
+	ErrInvalidDomainName = errors.New("invalid domain name")
+
 	// ErrInvalidDUIDLLTTime is returned when a time before midnight (UTC),
 	// January 1, 2000 is used in NewDUIDLLT.
 	ErrInvalidDUIDLLTTime = errors.New("DUID-LLT time must be after midnight (UTC), January 1, 2000")
 
+	// ErrInvalidDUIDText is returned by ParseDUID and the UnmarshalText
+	// methods of DUID types when a DUID is not in its canonical text form.
+	ErrInvalidDUIDText = errors.New("DUID text must be colon-separated hexadecimal bytes")
+
 	// ErrInvalidIP is returned when an input net.IP value is not recognized as a
 	// valid IPv6 address.
 	ErrInvalidIP = errors.New("IP must be an IPv6 address")
 
+	// ErrInvalidServerIP is returned when an input net.IP value is not
+	// usable as the address of a server, such as a DNS recursive name
+	// server, because it is unspecified or a multicast address.
+	ErrInvalidServerIP = errors.New("server IP must not be unspecified or multicast")
+
 	// ErrInvalidLifetimes is returned when an input preferred lifetime is shorter
 	// than a valid lifetime parameter.
 	ErrInvalidLifetimes = errors.New("preferred lifetime must be less than valid lifetime")
 
+	// ErrInvalidReconfigureMessage is returned when a ReconfigureMessage
+	// is not one of Renew, Rebind, or Information-request.
+	ErrInvalidReconfigureMessage = errors.New("reconfigure message type must be Renew, Rebind, or Information-request")
+
+	// ErrInvalidRelayMessageType is returned when a RelayMessage does not
+	// contain a Relay-forward or Relay-reply message type.
+	ErrInvalidRelayMessageType = errors.New("relay message type must be Relay-forward or Relay-reply")
+
+	// ErrInvalidT1T2 is returned when an IANA or IAPD has a T1 value which
+	// is greater than its non-zero T2 value.
+	ErrInvalidT1T2 = errors.New("T1 must not be greater than T2")
+
+	// ErrNotAdvertise is returned by SummarizeAdvertise when a packet is
+	// not an Advertise message.
+	ErrNotAdvertise = errors.New("packet must be an Advertise message")
+
+	// ErrNotEncapsulating is returned by GetNested when an option in its
+	// path does not encapsulate other options.
+	ErrNotEncapsulating = errors.New("option does not encapsulate other options")
+
+	// ErrUnsupportedAuthAlgorithm is returned by AuthSign and AuthVerify
+	// when an Authentication option uses an algorithm other than
+	// AuthAlgorithmHMACMD5.
+	ErrUnsupportedAuthAlgorithm = errors.New("unsupported authentication algorithm")
+
 	// ErrParseHardwareType is returned when a valid hardware type could
 	// not be found for a given interface.
 	ErrParseHardwareType = errors.New("could not parse hardware type for interface")
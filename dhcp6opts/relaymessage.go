@@ -8,6 +8,10 @@ import (
 	"github.com/mdlayher/dhcp6/internal/buffer"
 )
 
+// HopCountLimit is the maximum number of relay agents which may relay a
+// single message, as defined by HOP_COUNT_LIMIT in RFC 3315, Section 5.6.
+const HopCountLimit = 32
+
 // RelayMessage represents a raw RelayMessage generated by DHCPv6 relay agent, using RFC 3315,
 // Section 7.
 type RelayMessage struct {
@@ -62,15 +66,35 @@ func (rm *RelayMessage) MarshalBinary() ([]byte, error) {
 // UnmarshalBinary unmarshals a raw byte slice into a RelayMessage.
 //
 // If the byte slice does not contain enough data to form a valid RelayMessage,
-// ErrInvalidPacket is returned.
+// ErrInvalidPacket is returned.  If the message type is not Relay-forward or
+// Relay-reply, ErrInvalidRelayMessageType is returned.
 func (rm *RelayMessage) UnmarshalBinary(p []byte) error {
+	return rm.unmarshalBinary(p, true)
+}
+
+// UnmarshalBinaryLenient unmarshals a raw byte slice into a RelayMessage,
+// like UnmarshalBinary, but accepts any message type.  It is intended for
+// diagnostics, such as inspecting malformed traffic, and should not be used
+// to process messages.
+func (rm *RelayMessage) UnmarshalBinaryLenient(p []byte) error {
+	return rm.unmarshalBinary(p, false)
+}
+
+// unmarshalBinary implements UnmarshalBinary, optionally validating the
+// message type.
+func (rm *RelayMessage) unmarshalBinary(p []byte, checkType bool) error {
 	b := buffer.New(p)
 	// RelayMessage must contain at least message type, hop-count, link-address and peer-address
 	if b.Len() < 34 {
 		return io.ErrUnexpectedEOF
 	}
 
-	rm.MessageType = dhcp6.MessageType(b.Read8())
+	mt := dhcp6.MessageType(b.Read8())
+	if checkType && mt != dhcp6.MessageTypeRelayForw && mt != dhcp6.MessageTypeRelayRepl {
+		return ErrInvalidRelayMessageType
+	}
+
+	rm.MessageType = mt
 	rm.HopCount = b.Read8()
 
 	rm.LinkAddress = make(net.IP, net.IPv6len)
@@ -85,3 +109,148 @@ func (rm *RelayMessage) UnmarshalBinary(p []byte) error {
 	}
 	return nil
 }
+
+// Decapsulate unwraps the message carried in rm's Relay Message option,
+// along with any further relay messages of the same type nested within it.
+// It returns the innermost client or server message, and the relay messages
+// it passed through, ordered from outermost (rm itself) to innermost.
+//
+// If any relay message does not carry a Relay Message option, or more than
+// 32 relay messages are nested, ErrInvalidPacket is returned.
+func (rm *RelayMessage) Decapsulate() (*dhcp6.Packet, []*RelayMessage, error) {
+	relays := []*RelayMessage{rm}
+	for {
+		// Every relay message must carry a Relay Message option.
+		msg, err := relays[len(relays)-1].Options.GetOne(dhcp6.OptionRelayMsg)
+		if err != nil {
+			return nil, nil, dhcp6.ErrInvalidPacket
+		}
+
+		if len(msg) == 0 || dhcp6.MessageType(msg[0]) != rm.MessageType {
+			p := new(dhcp6.Packet)
+			if err := p.UnmarshalBinary(msg); err != nil {
+				return nil, nil, err
+			}
+
+			return p, relays, nil
+		}
+
+		if len(relays) == HopCountLimit {
+			return nil, nil, dhcp6.ErrInvalidPacket
+		}
+
+		next := new(RelayMessage)
+		if err := next.UnmarshalBinary(msg); err != nil {
+			return nil, nil, dhcp6.ErrInvalidPacket
+		}
+
+		relays = append(relays, next)
+	}
+}
+
+// NewRelayForward creates a new Relay-forward message which encapsulates
+// inner, a message received directly from a client, as described in RFC
+// 3315, Section 20.1.1.  The hop count of the new message is zero.  link and
+// peer specify the link-address and peer-address of the new message; link
+// may be the unspecified address.
+//
+// To relay a Relay-forward message received from another relay agent, use
+// WrapForward instead.
+//
+// If link or peer is not an IPv6 address, ErrInvalidIP is returned.
+func NewRelayForward(link, peer net.IP, inner *dhcp6.Packet) (*RelayMessage, error) {
+	return newRelayMessage(dhcp6.MessageTypeRelayForw, 0, link, peer, inner)
+}
+
+// NewRelayReply creates a new Relay-reply message which encapsulates inner,
+// a server's response to the client message carried in forward, as
+// described in RFC 3315, Section 20.3.  The hop count, link-address, and
+// peer-address are copied from forward, along with its Interface-ID option,
+// if present.
+//
+// If forward is not a Relay-forward message, ErrInvalidRelayMessageType is
+// returned.  If its link-address or peer-address is not an IPv6 address,
+// ErrInvalidIP is returned.
+func NewRelayReply(forward *RelayMessage, inner *dhcp6.Packet) (*RelayMessage, error) {
+	if forward.MessageType != dhcp6.MessageTypeRelayForw {
+		return nil, ErrInvalidRelayMessageType
+	}
+
+	rm, err := newRelayMessage(dhcp6.MessageTypeRelayRepl, forward.HopCount,
+		forward.LinkAddress, forward.PeerAddress, inner)
+	if err != nil {
+		return nil, err
+	}
+
+	if id, err := forward.Options.GetOne(dhcp6.OptionInterfaceID); err == nil {
+		rm.Options.AddRaw(dhcp6.OptionInterfaceID, id)
+	}
+
+	return rm, nil
+}
+
+// newRelayMessage creates a relay message of type mt which encapsulates the
+// client or server message inner.
+func newRelayMessage(mt dhcp6.MessageType, hops uint8, link, peer net.IP, inner *dhcp6.Packet) (*RelayMessage, error) {
+	if !isIPv6(link) || !isIPv6(peer) {
+		return nil, ErrInvalidIP
+	}
+
+	var msg RelayMessageOption
+	if err := msg.SetClientServerMessage(inner); err != nil {
+		return nil, err
+	}
+
+	rm := &RelayMessage{
+		MessageType: mt,
+		HopCount:    hops,
+		LinkAddress: link,
+		PeerAddress: peer,
+		Options:     make(dhcp6.Options),
+	}
+	if err := rm.Options.Add(dhcp6.OptionRelayMsg, &msg); err != nil {
+		return nil, err
+	}
+
+	return rm, nil
+}
+
+// WrapForward creates a new Relay-forward message which encapsulates inner, a
+// Relay-forward message received from another relay agent, as described in
+// RFC 3315, Section 20.1.2.  The hop count of the new message is one greater
+// than the hop count of inner.  link and peer specify the link-address and
+// peer-address of the new message; link may be the unspecified address.
+//
+// If inner is not a Relay-forward message, ErrInvalidRelayMessageType is
+// returned.  If the hop count of inner has already reached HopCountLimit,
+// ErrHopCountLimit is returned, and the message must be discarded.  If link
+// or peer is not an IPv6 address, ErrInvalidIP is returned.
+func WrapForward(inner *RelayMessage, link, peer net.IP) (*RelayMessage, error) {
+	if inner.MessageType != dhcp6.MessageTypeRelayForw {
+		return nil, ErrInvalidRelayMessageType
+	}
+	if inner.HopCount >= HopCountLimit {
+		return nil, ErrHopCountLimit
+	}
+	if !isIPv6(link) || !isIPv6(peer) {
+		return nil, ErrInvalidIP
+	}
+
+	rm := &RelayMessage{
+		MessageType: dhcp6.MessageTypeRelayForw,
+		HopCount:    inner.HopCount + 1,
+		LinkAddress: link,
+		PeerAddress: peer,
+		Options:     make(dhcp6.Options),
+	}
+	if err := rm.Options.Add(dhcp6.OptionRelayMsg, inner); err != nil {
+		return nil, err
+	}
+
+	return rm, nil
+}
+
+// isIPv6 reports whether ip is an IPv6 address.
+func isIPv6(ip net.IP) bool {
+	return ip.To16() != nil && ip.To4() == nil
+}
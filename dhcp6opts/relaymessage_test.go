@@ -90,15 +90,21 @@ func TestRelayMessageUnmarshalBinary(t *testing.T) {
 			buf:  make([]byte, 33),
 			err:  io.ErrUnexpectedEOF,
 		},
+		{
+			desc: "Solicit message type, malformed packet",
+			buf:  []byte{1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+			err:  ErrInvalidRelayMessageType,
+		},
 		{
 			desc: "invalid options in packet",
-			buf:  []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 0, 1},
+			buf:  []byte{12, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 0, 1},
 			err:  dhcp6.ErrInvalidPacket,
 		},
 		{
 			desc: "length 34 buffer, OK",
-			buf:  []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+			buf:  []byte{13, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
 			relayMsg: &RelayMessage{
+				MessageType: dhcp6.MessageTypeRelayRepl,
 				LinkAddress: net.IP(make([]byte, net.IPv6len)),
 				PeerAddress: net.IP(make([]byte, net.IPv6len)),
 				Options:     make(dhcp6.Options),
@@ -134,3 +140,457 @@ func TestRelayMessageUnmarshalBinary(t *testing.T) {
 		}
 	}
 }
+
+// TestRelayMessageUnmarshalBinaryLenient verifies that
+// RelayMessage.UnmarshalBinaryLenient accepts any message type.
+func TestRelayMessageUnmarshalBinaryLenient(t *testing.T) {
+	buf := make([]byte, 34)
+	buf[0] = byte(dhcp6.MessageTypeSolicit)
+
+	rm := new(RelayMessage)
+	if err := rm.UnmarshalBinaryLenient(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := dhcp6.MessageTypeSolicit, rm.MessageType; want != got {
+		t.Fatalf("unexpected message type: %v != %v", want, got)
+	}
+}
+
+// TestRelayMessageOptionBinary verifies that RelayMessageOption values
+// round-trip through MarshalBinary and UnmarshalBinary, and that neither
+// method aliases its input or internal state.
+func TestRelayMessageOptionBinary(t *testing.T) {
+	in := []byte{1, 0, 1, 2}
+
+	var r RelayMessageOption
+	if err := r.UnmarshalBinary(in); err != nil {
+		t.Fatal(err)
+	}
+
+	// Modifying the input must not modify the option.
+	in[0] = 0xff
+	if want, got := []byte{1, 0, 1, 2}, []byte(r); !bytes.Equal(want, got) {
+		t.Fatalf("unexpected RelayMessageOption after modifying input:\n- want: %v\n-  got: %v", want, got)
+	}
+
+	b, err := r.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := []byte{1, 0, 1, 2}, b; !bytes.Equal(want, got) {
+		t.Fatalf("unexpected RelayMessageOption bytes:\n- want: %v\n-  got: %v", want, got)
+	}
+
+	// Modifying the output must not modify the option.
+	b[0] = 0xff
+	if want, got := []byte{1, 0, 1, 2}, []byte(r); !bytes.Equal(want, got) {
+		t.Fatalf("unexpected RelayMessageOption after modifying output:\n- want: %v\n-  got: %v", want, got)
+	}
+}
+
+// TestRelayMessageOptionClientServerMessage verifies that a Packet set using
+// SetClientServerMessage can be parsed back out of a RelayMessageOption.
+func TestRelayMessageOptionClientServerMessage(t *testing.T) {
+	p := &dhcp6.Packet{
+		MessageType:   dhcp6.MessageTypeSolicit,
+		TransactionID: [3]byte{0, 1, 2},
+		Options: dhcp6.Options{
+			dhcp6.OptionRapidCommit: [][]byte{{}},
+		},
+	}
+
+	var r RelayMessageOption
+	if err := r.SetClientServerMessage(p); err != nil {
+		t.Fatal(err)
+	}
+
+	// Round-trip the option through an Options map, as a relay agent would.
+	o := make(dhcp6.Options)
+	if err := o.Add(dhcp6.OptionRelayMsg, &r); err != nil {
+		t.Fatal(err)
+	}
+	rmo, err := GetRelayMessageOption(o)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := rmo.ClientServerMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := p, out; !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected client/server message:\n- want: %v\n-  got: %v", want, got)
+	}
+}
+
+// TestRelayMessageOptionRelayMessage verifies that a RelayMessage set using
+// SetRelayMessage can be parsed back out of a RelayMessageOption.
+func TestRelayMessageOptionRelayMessage(t *testing.T) {
+	var inner RelayMessageOption
+	if err := inner.SetClientServerMessage(&dhcp6.Packet{
+		MessageType:   dhcp6.MessageTypeSolicit,
+		TransactionID: [3]byte{0, 1, 2},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	rm := &RelayMessage{
+		MessageType: dhcp6.MessageTypeRelayForw,
+		HopCount:    1,
+		LinkAddress: net.ParseIP("2001:db8::1"),
+		PeerAddress: net.ParseIP("fe80::1"),
+		Options:     make(dhcp6.Options),
+	}
+	if err := rm.Options.Add(dhcp6.OptionRelayMsg, &inner); err != nil {
+		t.Fatal(err)
+	}
+
+	var r RelayMessageOption
+	if err := r.SetRelayMessage(rm); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := r.RelayMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := rm, out; !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected relay message:\n- want: %v\n-  got: %v", want, got)
+	}
+
+	rmo, err := GetRelayMessageOption(out.Options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := rmo.ClientServerMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := dhcp6.MessageTypeSolicit, p.MessageType; want != got {
+		t.Fatalf("unexpected embedded message type: %v != %v", want, got)
+	}
+}
+
+// relayForward encapsulates msg in a Relay-forward message with the input
+// hop count.
+func relayForward(hops uint8, msg []byte) *RelayMessage {
+	rm := &RelayMessage{
+		MessageType: dhcp6.MessageTypeRelayForw,
+		HopCount:    hops,
+		LinkAddress: net.IPv6zero,
+		PeerAddress: net.IPv6zero,
+		Options:     make(dhcp6.Options),
+	}
+	if msg != nil {
+		rm.Options.AddRaw(dhcp6.OptionRelayMsg, msg)
+	}
+
+	return rm
+}
+
+// TestRelayMessageDecapsulate verifies that RelayMessage.Decapsulate unwraps
+// nested relay messages in order and returns the innermost client message.
+func TestRelayMessageDecapsulate(t *testing.T) {
+	p := &dhcp6.Packet{
+		MessageType:   dhcp6.MessageTypeSolicit,
+		TransactionID: [3]byte{1, 2, 3},
+		Options:       make(dhcp6.Options),
+	}
+	msg, err := p.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inner := relayForward(0, msg)
+	ib, err := inner.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	outer := relayForward(1, ib)
+
+	gp, relays, err := outer.Decapsulate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := p, gp; !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected Packet:\n- want: %v\n-  got: %v", want, got)
+	}
+
+	if want, got := 2, len(relays); want != got {
+		t.Fatalf("unexpected number of relays: %v != %v", want, got)
+	}
+	if want, got := outer, relays[0]; want != got {
+		t.Fatalf("unexpected outermost relay: %v != %v", want, got)
+	}
+	if want, got := uint8(0), relays[1].HopCount; want != got {
+		t.Fatalf("unexpected innermost relay hop count: %v != %v", want, got)
+	}
+}
+
+// TestRelayMessageDecapsulateErrors verifies that RelayMessage.Decapsulate
+// rejects malformed or excessively nested relay messages.
+func TestRelayMessageDecapsulateErrors(t *testing.T) {
+	msg := []byte{byte(dhcp6.MessageTypeSolicit), 1, 2, 3}
+
+	// Nest one more relay message than is permitted.
+	tooDeep := relayForward(0, msg)
+	for i := 0; i < HopCountLimit; i++ {
+		b, err := tooDeep.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		tooDeep = relayForward(uint8(i+1), b)
+	}
+
+	var tests = []struct {
+		desc string
+		rm   *RelayMessage
+		err  error
+	}{
+		{
+			desc: "no Relay Message option",
+			rm:   relayForward(0, nil),
+			err:  dhcp6.ErrInvalidPacket,
+		},
+		{
+			desc: "truncated client message",
+			rm:   relayForward(0, msg[:2]),
+			err:  dhcp6.ErrInvalidPacket,
+		},
+		{
+			desc: "truncated relay message",
+			rm:   relayForward(0, []byte{byte(dhcp6.MessageTypeRelayForw), 0}),
+			err:  dhcp6.ErrInvalidPacket,
+		},
+		{
+			desc: "too many relay messages",
+			rm:   tooDeep,
+			err:  dhcp6.ErrInvalidPacket,
+		},
+	}
+
+	for i, tt := range tests {
+		if _, _, err := tt.rm.Decapsulate(); err != tt.err {
+			t.Fatalf("[%02d] test %q, unexpected error: %v != %v",
+				i, tt.desc, tt.err, err)
+		}
+	}
+}
+
+// TestWrapForward verifies that WrapForward increments the hop count of a
+// relayed Relay-forward message and embeds it in a Relay Message option.
+func TestWrapForward(t *testing.T) {
+	inner := relayForward(3, []byte{byte(dhcp6.MessageTypeSolicit), 1, 2, 3})
+	link := net.ParseIP("2001:db8::1")
+	peer := net.ParseIP("fe80::1")
+
+	rm, err := WrapForward(inner, link, peer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := uint8(4), rm.HopCount; want != got {
+		t.Fatalf("unexpected hop count: %v != %v", want, got)
+	}
+	if want, got := dhcp6.MessageTypeRelayForw, rm.MessageType; want != got {
+		t.Fatalf("unexpected message type: %v != %v", want, got)
+	}
+	if !link.Equal(rm.LinkAddress) || !peer.Equal(rm.PeerAddress) {
+		t.Fatalf("unexpected addresses: %v, %v", rm.LinkAddress, rm.PeerAddress)
+	}
+
+	b, err := rm.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := new(RelayMessage)
+	if err := out.UnmarshalBinary(b); err != nil {
+		t.Fatal(err)
+	}
+
+	_, relays, err := out.Decapsulate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := 2, len(relays); want != got {
+		t.Fatalf("unexpected number of relays: %v != %v", want, got)
+	}
+	if want, got := uint8(3), relays[1].HopCount; want != got {
+		t.Fatalf("unexpected inner relay hop count: %v != %v", want, got)
+	}
+}
+
+// TestWrapForwardErrors verifies that WrapForward enforces HopCountLimit and
+// rejects invalid input.
+func TestWrapForwardErrors(t *testing.T) {
+	msg := []byte{byte(dhcp6.MessageTypeSolicit), 1, 2, 3}
+	link := net.ParseIP("2001:db8::1")
+	peer := net.ParseIP("fe80::1")
+
+	reply := relayForward(0, msg)
+	reply.MessageType = dhcp6.MessageTypeRelayRepl
+
+	var tests = []struct {
+		desc       string
+		inner      *RelayMessage
+		link, peer net.IP
+		err        error
+	}{
+		{
+			desc:  "hop count one below limit",
+			inner: relayForward(HopCountLimit-1, msg),
+			link:  link,
+			peer:  peer,
+		},
+		{
+			desc:  "hop count at limit",
+			inner: relayForward(HopCountLimit, msg),
+			link:  link,
+			peer:  peer,
+			err:   ErrHopCountLimit,
+		},
+		{
+			desc:  "Relay-reply message",
+			inner: reply,
+			link:  link,
+			peer:  peer,
+			err:   ErrInvalidRelayMessageType,
+		},
+		{
+			desc:  "unspecified link-address",
+			inner: relayForward(0, msg),
+			link:  net.IPv6unspecified,
+			peer:  peer,
+		},
+		{
+			desc:  "IPv4 peer-address",
+			inner: relayForward(0, msg),
+			link:  link,
+			peer:  net.IPv4(192, 0, 2, 1),
+			err:   ErrInvalidIP,
+		},
+		{
+			desc:  "nil link-address",
+			inner: relayForward(0, msg),
+			peer:  peer,
+			err:   ErrInvalidIP,
+		},
+	}
+
+	for i, tt := range tests {
+		if _, err := WrapForward(tt.inner, tt.link, tt.peer); tt.err != err {
+			t.Fatalf("[%02d] test %q, unexpected error: %v != %v",
+				i, tt.desc, tt.err, err)
+		}
+	}
+}
+
+// TestNewRelayForwardReply verifies that NewRelayForward and NewRelayReply
+// create relay messages which round-trip a client and server message.
+func TestNewRelayForwardReply(t *testing.T) {
+	link := net.ParseIP("2001:db8::1")
+	peer := net.ParseIP("fe80::1")
+
+	solicit := &dhcp6.Packet{
+		MessageType:   dhcp6.MessageTypeSolicit,
+		TransactionID: [3]byte{1, 2, 3},
+		Options:       make(dhcp6.Options),
+	}
+
+	rf, err := NewRelayForward(link, peer, solicit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rf.Options.AddRaw(dhcp6.OptionInterfaceID, []byte("eth0"))
+
+	if want, got := dhcp6.MessageTypeRelayForw, rf.MessageType; want != got {
+		t.Fatalf("unexpected message type: %v != %v", want, got)
+	}
+	if want, got := uint8(0), rf.HopCount; want != got {
+		t.Fatalf("unexpected hop count: %v != %v", want, got)
+	}
+
+	p, _, err := rf.Decapsulate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := solicit, p; !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected Packet:\n- want: %v\n-  got: %v", want, got)
+	}
+
+	// Reply to a cascaded Relay-forward, so the hop count is copied.
+	outer, err := WrapForward(rf, net.IPv6unspecified, link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	outer.Options.AddRaw(dhcp6.OptionInterfaceID, []byte("eth1"))
+
+	advertise := &dhcp6.Packet{
+		MessageType:   dhcp6.MessageTypeAdvertise,
+		TransactionID: [3]byte{1, 2, 3},
+		Options:       make(dhcp6.Options),
+	}
+
+	rr, err := NewRelayReply(outer, advertise)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := dhcp6.MessageTypeRelayRepl, rr.MessageType; want != got {
+		t.Fatalf("unexpected message type: %v != %v", want, got)
+	}
+	if want, got := uint8(1), rr.HopCount; want != got {
+		t.Fatalf("unexpected hop count: %v != %v", want, got)
+	}
+	if !outer.LinkAddress.Equal(rr.LinkAddress) || !outer.PeerAddress.Equal(rr.PeerAddress) {
+		t.Fatalf("unexpected addresses: %v, %v", rr.LinkAddress, rr.PeerAddress)
+	}
+
+	id, err := GetInterfaceID(rr.Options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := "eth1", string(id); want != got {
+		t.Fatalf("unexpected interface-ID: %q != %q", want, got)
+	}
+
+	p, _, err = rr.Decapsulate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := advertise, p; !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected Packet:\n- want: %v\n-  got: %v", want, got)
+	}
+}
+
+// TestNewRelayForwardReplyErrors verifies that NewRelayForward and
+// NewRelayReply reject invalid addresses and message types.
+func TestNewRelayForwardReplyErrors(t *testing.T) {
+	p := &dhcp6.Packet{MessageType: dhcp6.MessageTypeSolicit}
+	link := net.ParseIP("2001:db8::1")
+	peer := net.ParseIP("fe80::1")
+
+	if _, err := NewRelayForward(net.IPv4(192, 0, 2, 1), peer, p); err != ErrInvalidIP {
+		t.Fatalf("unexpected error for IPv4 link-address: %v", err)
+	}
+	if _, err := NewRelayForward(link, nil, p); err != ErrInvalidIP {
+		t.Fatalf("unexpected error for nil peer-address: %v", err)
+	}
+
+	reply := relayForward(0, nil)
+	reply.MessageType = dhcp6.MessageTypeRelayRepl
+	if _, err := NewRelayReply(reply, p); err != ErrInvalidRelayMessageType {
+		t.Fatalf("unexpected error for Relay-reply message: %v", err)
+	}
+
+	short := relayForward(0, nil)
+	short.PeerAddress = net.IP{0xfe, 0x80}
+	if _, err := NewRelayReply(short, p); err != ErrInvalidIP {
+		t.Fatalf("unexpected error for short peer-address: %v", err)
+	}
+}
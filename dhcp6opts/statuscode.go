@@ -40,6 +40,60 @@ func (s *StatusCode) MarshalBinary() ([]byte, error) {
 	return b.Data(), nil
 }
 
+// statusCodeContainers maps option codes which may encapsulate Status Code
+// options to the length of their fixed fields, which precede their
+// encapsulated options.
+var statusCodeContainers = map[dhcp6.OptionCode]int{
+	// IAID, T1, T2
+	dhcp6.OptionIANA: 12,
+	// IAID
+	dhcp6.OptionIATA: 4,
+	// IPv6 address, preferred lifetime, valid lifetime
+	dhcp6.OptionIAAddr: 24,
+	// IAID, T1, T2
+	dhcp6.OptionIAPD: 12,
+	// Preferred lifetime, valid lifetime, prefix length, IPv6 prefix
+	dhcp6.OptionIAPrefix: 25,
+}
+
+// RemoveStatusCodes removes all Status Code options from o, including those
+// encapsulated within IA_NA, IA_TA, IA Address, IA_PD, and IA Prefix
+// options.  It is useful when reusing a set of Options as a template for
+// multiple replies, so that stale status codes do not leak between them.
+//
+// Options which encapsulate Status Code options are re-encoded in place.
+// If any encapsulated options are malformed, dhcp6.ErrInvalidOptions is
+// returned, and o may be partially modified.
+func RemoveStatusCodes(o dhcp6.Options) error {
+	o.RemoveStatusCodes()
+
+	for code, n := range statusCodeContainers {
+		for i, v := range o[code] {
+			if len(v) < n {
+				return dhcp6.ErrInvalidOptions
+			}
+
+			var nested dhcp6.Options
+			if err := nested.UnmarshalBinary(v[n:]); err != nil {
+				return err
+			}
+			if err := RemoveStatusCodes(nested); err != nil {
+				return err
+			}
+
+			nb, err := nested.MarshalBinary()
+			if err != nil {
+				return err
+			}
+
+			// Copy fixed fields to avoid modifying the original value.
+			o[code][i] = append(v[:n:n], nb...)
+		}
+	}
+
+	return nil
+}
+
 // UnmarshalBinary unmarshals a raw byte slice into a StatusCode.
 //
 // If the byte slice does not contain enough data to form a valid StatusCode,
@@ -2,9 +2,12 @@ package dhcp6opts
 
 import (
 	"bytes"
+	"encoding"
 	"io"
+	"net"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/mdlayher/dhcp6"
 )
@@ -88,3 +91,126 @@ func TestStatusCodeUnmarshalBinary(t *testing.T) {
 		}
 	}
 }
+
+// TestRemoveStatusCodes verifies that RemoveStatusCodes removes top-level
+// and encapsulated Status Code options, and leaves other options intact.
+func TestRemoveStatusCodes(t *testing.T) {
+	sc := NewStatusCode(dhcp6.StatusNoAddrsAvail, "stale")
+
+	addrOpts := make(dhcp6.Options)
+	if err := addrOpts.Add(dhcp6.OptionStatusCode, sc); err != nil {
+		t.Fatal(err)
+	}
+	addr, err := NewIAAddr(net.ParseIP("2001:db8::1"), 1*time.Hour, 2*time.Hour, addrOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	naOpts := make(dhcp6.Options)
+	if err := naOpts.Add(dhcp6.OptionIAAddr, addr); err != nil {
+		t.Fatal(err)
+	}
+	if err := naOpts.Add(dhcp6.OptionStatusCode, sc); err != nil {
+		t.Fatal(err)
+	}
+
+	prefixOpts := make(dhcp6.Options)
+	if err := prefixOpts.Add(dhcp6.OptionStatusCode, sc); err != nil {
+		t.Fatal(err)
+	}
+	prefix, err := NewIAPrefix(1*time.Hour, 2*time.Hour, 64, net.ParseIP("2001:db8::"), prefixOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pdOpts := make(dhcp6.Options)
+	if err := pdOpts.Add(dhcp6.OptionIAPrefix, prefix); err != nil {
+		t.Fatal(err)
+	}
+
+	o := make(dhcp6.Options)
+	for _, opt := range []struct {
+		code  dhcp6.OptionCode
+		value encoding.BinaryMarshaler
+	}{
+		{code: dhcp6.OptionStatusCode, value: sc},
+		{code: dhcp6.OptionIANA, value: NewIANA([4]byte{0, 0, 0, 1}, 30*time.Minute, 48*time.Minute, naOpts)},
+		{code: dhcp6.OptionIAPD, value: NewIAPD([4]byte{0, 0, 0, 2}, 30*time.Minute, 48*time.Minute, pdOpts)},
+		{code: dhcp6.OptionPreference, value: Preference(255)},
+	} {
+		if err := o.Add(opt.code, opt.value); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := RemoveStatusCodes(o); err != nil {
+		t.Fatal(err)
+	}
+
+	noStatus := func(desc string, o dhcp6.Options) {
+		if _, err := o.Get(dhcp6.OptionStatusCode); err != dhcp6.ErrOptionNotPresent {
+			t.Fatalf("unexpected Status Code in %s options after removal: %v", desc, err)
+		}
+	}
+
+	noStatus("top-level", o)
+	if _, err := GetPreference(o); err != nil {
+		t.Fatalf("unexpected error for Preference: %v", err)
+	}
+
+	iana, err := GetIANA(o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	noStatus("IA_NA", iana[0].Options)
+
+	iaaddrs, err := iana[0].IAAddrs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	noStatus("IA Address", iaaddrs[0].Options)
+	if want, got := addr.IP, iaaddrs[0].IP; !want.Equal(got) {
+		t.Fatalf("unexpected IA Address: %v != %v", want, got)
+	}
+
+	iapd, err := GetIAPD(o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	noStatus("IA_PD", iapd[0].Options)
+
+	iaprefixes, err := GetIAPrefix(iapd[0].Options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	noStatus("IA Prefix", iaprefixes[0].Options)
+}
+
+// TestRemoveStatusCodesInvalidOptions verifies that RemoveStatusCodes
+// returns an error when an encapsulating option is malformed.
+func TestRemoveStatusCodesInvalidOptions(t *testing.T) {
+	var tests = []struct {
+		desc    string
+		options dhcp6.Options
+	}{
+		{
+			desc: "IA_NA too short",
+			options: dhcp6.Options{
+				dhcp6.OptionIANA: [][]byte{{0, 0, 0, 1}},
+			},
+		},
+		{
+			desc: "IA_TA with truncated options",
+			options: dhcp6.Options{
+				dhcp6.OptionIATA: [][]byte{{0, 0, 0, 1, 0, 13, 0, 2}},
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		if want, got := dhcp6.ErrInvalidOptions, RemoveStatusCodes(tt.options); want != got {
+			t.Fatalf("[%02d] test %q, unexpected error: %v != %v",
+				i, tt.desc, want, got)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package dhcp6server
+
+import (
+	"encoding/binary"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/mdlayher/dhcp6"
+	"github.com/mdlayher/dhcp6/dhcp6opts"
+)
+
+// ErrInvalidDelegation is returned by DelegatePrefix when an upstream prefix
+// is not an IPv6 prefix, or cannot be divided into prefixes of the requested
+// length.
+var ErrInvalidDelegation = errors.New("upstream prefix cannot be divided into prefixes of the requested length")
+
+// DelegatePrefix deterministically carves a prefix of length prefixLength
+// out of an upstream prefix for the IA_PD identified by iaid, and returns an
+// IAPD containing it, for use by a requesting router which sub-delegates a
+// prefix it obtained itself, as described in RFC 3633.
+//
+// The IAID selects which of the available prefixes is delegated, so the same
+// IAID always receives the same prefix, and IAIDs which differ in their low
+// (prefixLength - upstream prefix length) bits receive distinct prefixes.
+// Because IAIDs are only unique per client, servers which delegate to many
+// clients should detect and resolve collisions themselves.
+//
+// T1 and T2 are set using RecommendedT1T2 and the preferred lifetime.  If
+// the preferred lifetime is greater than the valid lifetime,
+// dhcp6opts.ErrInvalidLifetimes is returned.
+func DelegatePrefix(upstream *net.IPNet, prefixLength uint8, iaid [4]byte, preferred time.Duration, valid time.Duration) (*dhcp6opts.IAPD, error) {
+	ones, bits := upstream.Mask.Size()
+	if bits != 8*net.IPv6len || len(upstream.IP) != net.IPv6len || upstream.IP.To4() != nil {
+		return nil, ErrInvalidDelegation
+	}
+	if int(prefixLength) < ones || int(prefixLength) > bits {
+		return nil, ErrInvalidDelegation
+	}
+
+	prefix := upstream.IP.Mask(upstream.Mask)
+
+	// Place the low bits of the IAID immediately before the end of the
+	// delegated prefix.
+	idx := binary.BigEndian.Uint32(iaid[:])
+	for b := 0; b < int(prefixLength)-ones && b < 32; b++ {
+		if idx>>uint(b)&1 == 0 {
+			continue
+		}
+
+		pos := int(prefixLength) - 1 - b
+		prefix[pos/8] |= 0x80 >> uint(pos%8)
+	}
+
+	iap, err := dhcp6opts.NewIAPrefix(preferred, valid, prefixLength, prefix, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	t1, t2 := dhcp6opts.RecommendedT1T2(preferred)
+	iapd := dhcp6opts.NewIAPD(iaid, t1, t2, nil)
+	if err := iapd.Options.Add(dhcp6.OptionIAPrefix, iap); err != nil {
+		return nil, err
+	}
+
+	return iapd, nil
+}
@@ -0,0 +1,189 @@
+package dhcp6server_test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/mdlayher/dhcp6/dhcp6opts"
+	"github.com/mdlayher/dhcp6/dhcp6server"
+)
+
+// TestDelegatePrefix verifies that DelegatePrefix deterministically carves
+// distinct prefixes out of an upstream prefix for distinct IAIDs.
+func TestDelegatePrefix(t *testing.T) {
+	_, upstream, err := net.ParseCIDR("2001:db8:1200::/40")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	delegate := func(iaid [4]byte) *dhcp6opts.IAPrefix {
+		iapd, err := dhcp6server.DelegatePrefix(upstream, 56, iaid, time.Hour, 2*time.Hour)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if want, got := iaid, iapd.IAID; want != got {
+			t.Fatalf("unexpected IAID: %v != %v", want, got)
+		}
+		if want, got := 30*time.Minute, iapd.T1; want != got {
+			t.Fatalf("unexpected T1: %v != %v", want, got)
+		}
+
+		prefixes, err := iapd.Prefixes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want, got := 1, len(prefixes); want != got {
+			t.Fatalf("unexpected number of prefixes: %v != %v", want, got)
+		}
+
+		return prefixes[0]
+	}
+
+	var tests = []struct {
+		desc   string
+		iaid   [4]byte
+		prefix string
+	}{
+		{
+			desc:   "IAID 0",
+			prefix: "2001:db8:1200::",
+		},
+		{
+			desc:   "IAID 1",
+			iaid:   [4]byte{0, 0, 0, 1},
+			prefix: "2001:db8:1200:100::",
+		},
+		{
+			desc:   "IAID 0x1ff",
+			iaid:   [4]byte{0, 0, 1, 0xff},
+			prefix: "2001:db8:1201:ff00::",
+		},
+	}
+
+	for i, tt := range tests {
+		iap := delegate(tt.iaid)
+
+		if want, got := net.ParseIP(tt.prefix), iap.Prefix; !want.Equal(got) {
+			t.Fatalf("[%02d] test %q, unexpected prefix: %v != %v",
+				i, tt.desc, want, got)
+		}
+		if want, got := uint8(56), iap.PrefixLength; want != got {
+			t.Fatalf("[%02d] test %q, unexpected prefix length: %v != %v",
+				i, tt.desc, want, got)
+		}
+
+		// The same IAID must always receive the same prefix
+		if want, got := iap.Prefix, delegate(tt.iaid).Prefix; !want.Equal(got) {
+			t.Fatalf("[%02d] test %q, prefix changed between calls: %v != %v",
+				i, tt.desc, want, got)
+		}
+	}
+
+	// Every possible IAID receives a distinct prefix within upstream
+	seen := make(map[string]struct{})
+	for i := 0; i < 1<<8; i++ {
+		p := delegate([4]byte{0, 0, 0, byte(i)}).Prefix
+		if !upstream.Contains(p) {
+			t.Fatalf("prefix %v not within %v", p, upstream)
+		}
+		if _, ok := seen[p.String()]; ok {
+			t.Fatalf("duplicate prefix %v for IAID %d", p, i)
+		}
+		seen[p.String()] = struct{}{}
+	}
+}
+
+// TestDelegatePrefixLongLifetime verifies that DelegatePrefix sets valid T1
+// and T2 values for the longest finite preferred lifetime.
+func TestDelegatePrefixLongLifetime(t *testing.T) {
+	_, upstream, err := net.ParseCIDR("2001:db8:1200::/40")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	preferred := dhcp6opts.InfiniteLifetime - time.Second
+	iapd, err := dhcp6server.DelegatePrefix(upstream, 56, [4]byte{}, preferred, dhcp6opts.InfiniteLifetime)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if iapd.T1 < 0 || iapd.T1 > iapd.T2 || iapd.T2 > preferred {
+		t.Fatalf("expected 0 <= T1 <= T2 <= preferred, but got T1: %v, T2: %v",
+			iapd.T1, iapd.T2)
+	}
+
+	// T1 and T2 must survive encoding as 32-bit second counts.
+	b, err := iapd.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := new(dhcp6opts.IAPD)
+	if err := got.UnmarshalBinary(b); err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := iapd.T1, got.T1; want.Truncate(time.Second) != got {
+		t.Fatalf("unexpected T1 after encoding: %v != %v", want, got)
+	}
+	if want, got := iapd.T2, got.T2; want.Truncate(time.Second) != got {
+		t.Fatalf("unexpected T2 after encoding: %v != %v", want, got)
+	}
+}
+
+// TestDelegatePrefixErrors verifies that DelegatePrefix rejects invalid
+// upstream prefixes, prefix lengths, and lifetimes.
+func TestDelegatePrefixErrors(t *testing.T) {
+	_, v6, err := net.ParseCIDR("2001:db8::/48")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, v4, err := net.ParseCIDR("192.0.2.0/24")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = []struct {
+		desc      string
+		upstream  *net.IPNet
+		length    uint8
+		preferred time.Duration
+		err       error
+	}{
+		{
+			desc:     "IPv4 upstream",
+			upstream: v4,
+			length:   28,
+			err:      dhcp6server.ErrInvalidDelegation,
+		},
+		{
+			desc:     "prefix length shorter than upstream",
+			upstream: v6,
+			length:   40,
+			err:      dhcp6server.ErrInvalidDelegation,
+		},
+		{
+			desc:     "prefix length too long",
+			upstream: v6,
+			length:   129,
+			err:      dhcp6server.ErrInvalidDelegation,
+		},
+		{
+			desc:      "preferred lifetime longer than valid",
+			upstream:  v6,
+			length:    56,
+			preferred: 3 * time.Hour,
+			err:       dhcp6opts.ErrInvalidLifetimes,
+		},
+	}
+
+	for i, tt := range tests {
+		_, err := dhcp6server.DelegatePrefix(tt.upstream, tt.length, [4]byte{}, tt.preferred, 2*time.Hour)
+		if want, got := tt.err, err; want != got {
+			t.Fatalf("[%02d] test %q, unexpected error: %v != %v",
+				i, tt.desc, want, got)
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package dhcp6server
 
 import (
+	"bytes"
+	"fmt"
 	"sync"
 
 	"github.com/mdlayher/dhcp6"
@@ -12,8 +14,32 @@ import (
 // for structuring your application, but may not be needed for very simple
 // DHCP servers.
 type ServeMux struct {
-	mu sync.RWMutex
-	m  map[dhcp6.MessageType]Handler
+	// Strict specifies whether Handle and HandleFunc should panic when a
+	// Handler is registered for a message type which is only sent by
+	// servers, such as Advertise or Reply.  A server never needs to handle
+	// these messages, so registering them is likely a bug.  By default,
+	// any message type may be registered.
+	Strict bool
+
+	mu       sync.RWMutex
+	m        map[dhcp6.MessageType]Handler
+	def      Handler
+	defaults map[dhcp6.MessageType]dhcp6.Options
+}
+
+// serverOriginated contains message types which are sent by servers, and
+// never handled by them.
+var serverOriginated = map[dhcp6.MessageType]struct{}{
+	dhcp6.MessageTypeAdvertise:        {},
+	dhcp6.MessageTypeReply:            {},
+	dhcp6.MessageTypeReconfigure:      {},
+	dhcp6.MessageTypeRelayRepl:        {},
+	dhcp6.MessageTypeLeasequeryReply:  {},
+	dhcp6.MessageTypeLeasequeryDone:   {},
+	dhcp6.MessageTypeLeasequeryData:   {},
+	dhcp6.MessageTypeReconfigureReply: {},
+	dhcp6.MessageTypeDHCPv4Response:   {},
+	dhcp6.MessageTypeAddrRegReply:     {},
 }
 
 // NewServeMux creates a new ServeMux which is ready to accept Handlers.
@@ -25,22 +51,47 @@ func NewServeMux() *ServeMux {
 
 // ServeDHCP implements Handler for ServeMux, and serves a DHCP request using
 // the appropriate handler for an input Request's MessageType.  If the
-// MessageType does not match a valid Handler, ServeDHCP does not invoke any
-// handlers, ignoring a client's request.
+// MessageType does not match a valid Handler, the default Handler registered
+// using HandleDefault is invoked.  If no default Handler is registered,
+// ServeDHCP does not invoke any handlers, ignoring a client's request.
 func (mux *ServeMux) ServeDHCP(w ResponseSender, r *Request) {
 	mux.mu.RLock()
 	defer mux.mu.RUnlock()
 	h, ok := mux.m[r.MessageType]
 	if !ok {
+		h = mux.def
+	}
+	if h == nil {
 		return
 	}
 
+	// Seed the response with defaults for the type of message which
+	// normally answers this request, so the Handler can inspect or
+	// override them.  If the Handler answers with another type, such as a
+	// Reply to a Solicit using rapid commit, the defaults are replaced
+	// with those for that type.
+	if rt, ok := responseTypes[r.MessageType]; ok && len(mux.defaults) > 0 {
+		ds := &defaultsSender{
+			ResponseSender: w,
+			defaults:       mux.defaults,
+		}
+		ds.applyDefaults(rt)
+		w = ds
+	}
+
 	h.ServeDHCP(w, r)
 }
 
 // Handle registers a MessageType and Handler with a ServeMux, so that
 // future requests with that MessageType will invoke the Handler.
+//
+// If mux.Strict is set and mt is a message type which is only sent by
+// servers, Handle panics.
 func (mux *ServeMux) Handle(mt dhcp6.MessageType, handler Handler) {
+	if _, ok := serverOriginated[mt]; ok && mux.Strict {
+		panic(fmt.Sprintf("dhcp6server: cannot handle server-originated message type %s", mt))
+	}
+
 	mux.mu.Lock()
 	mux.m[mt] = handler
 	mux.mu.Unlock()
@@ -52,3 +103,160 @@ func (mux *ServeMux) Handle(mt dhcp6.MessageType, handler Handler) {
 func (mux *ServeMux) HandleFunc(mt dhcp6.MessageType, handler func(ResponseSender, *Request)) {
 	mux.Handle(mt, HandlerFunc(handler))
 }
+
+// HandleDefault registers a Handler with a ServeMux which is invoked for
+// requests whose MessageType does not match any Handler registered using
+// Handle or HandleFunc.  A default Handler can be used to log unexpected
+// message types, or to reply with a Status Code option, rather than
+// silently ignoring a client's request.
+//
+// Handlers registered for a specific MessageType always take precedence
+// over the default Handler.  Passing a nil Handler removes the default.
+func (mux *ServeMux) HandleDefault(handler Handler) {
+	mux.mu.Lock()
+	mux.def = handler
+	mux.mu.Unlock()
+}
+
+// HandleFuncDefault registers a function as a HandlerFunc with a ServeMux,
+// which is invoked for requests whose MessageType does not match any other
+// Handler.
+func (mux *ServeMux) HandleFuncDefault(handler func(ResponseSender, *Request)) {
+	mux.HandleDefault(HandlerFunc(handler))
+}
+
+// SetDefaultOptions registers a set of options which are added to every
+// response of MessageType mt sent by a Handler registered with a ServeMux,
+// such as a Preference or DNS Recursive Name Servers option for each
+// Advertise and Reply.  Options are copied from o, and replace any defaults
+// previously registered for mt.  Passing a nil or empty Options map removes
+// the defaults for mt.
+//
+// Default options are added to the response's Options map before a Handler
+// is invoked, using the message type which normally answers a request:
+// Advertise for Solicit, Reply for Request, Confirm, Renew, Rebind, Release,
+// Decline, and Information-request, and the matching reply type for
+// Leasequery, Reconfigure-request, DHCPv4-query, and Addr-Reg-Inform.  A
+// Handler can inspect, replace, or remove any default using the Options
+// map.
+//
+// If a Handler answers with a different message type, such as a Reply to a
+// Solicit when HonorRapidCommit returns true, the defaults for that type
+// are used instead.  HonorRapidCommit swaps in the Reply defaults itself,
+// and Send does so for any other type.  Defaults which the Handler has
+// modified are kept.
+func (mux *ServeMux) SetDefaultOptions(mt dhcp6.MessageType, o dhcp6.Options) {
+	mux.mu.Lock()
+	defer mux.mu.Unlock()
+
+	// Copy the defaults map so that in-flight requests are unaffected.
+	defaults := make(map[dhcp6.MessageType]dhcp6.Options, len(mux.defaults)+1)
+	for k, v := range mux.defaults {
+		defaults[k] = v
+	}
+
+	if len(o) == 0 {
+		delete(defaults, mt)
+	} else {
+		defaults[mt] = copyOptions(o)
+	}
+
+	mux.defaults = defaults
+}
+
+// responseTypes maps client message types to the message type a server
+// normally sends in response, as described in RFC 3315, Section 15, and in
+// RFCs 5007, 6977, 7341, and 9686.
+var responseTypes = map[dhcp6.MessageType]dhcp6.MessageType{
+	dhcp6.MessageTypeSolicit:            dhcp6.MessageTypeAdvertise,
+	dhcp6.MessageTypeRequest:            dhcp6.MessageTypeReply,
+	dhcp6.MessageTypeConfirm:            dhcp6.MessageTypeReply,
+	dhcp6.MessageTypeRenew:              dhcp6.MessageTypeReply,
+	dhcp6.MessageTypeRebind:             dhcp6.MessageTypeReply,
+	dhcp6.MessageTypeRelease:            dhcp6.MessageTypeReply,
+	dhcp6.MessageTypeDecline:            dhcp6.MessageTypeReply,
+	dhcp6.MessageTypeInformationRequest: dhcp6.MessageTypeReply,
+	dhcp6.MessageTypeLeasequery:         dhcp6.MessageTypeLeasequeryReply,
+	dhcp6.MessageTypeReconfigureRequest: dhcp6.MessageTypeReconfigureReply,
+	dhcp6.MessageTypeDHCPv4Query:        dhcp6.MessageTypeDHCPv4Response,
+	dhcp6.MessageTypeAddrRegInform:      dhcp6.MessageTypeAddrRegReply,
+}
+
+// copyOptions returns a copy of o, including its option values.
+func copyOptions(o dhcp6.Options) dhcp6.Options {
+	c := make(dhcp6.Options, len(o))
+	for code, values := range o {
+		c[code] = copyValues(values)
+	}
+
+	return c
+}
+
+// A defaultsSender is a ResponseSender which tracks the default options
+// added to a response, so they can be replaced if the response is sent
+// using a different message type.
+type defaultsSender struct {
+	ResponseSender
+	defaults map[dhcp6.MessageType]dhcp6.Options
+
+	// mt is the message type whose defaults were last applied.
+	mt dhcp6.MessageType
+}
+
+// Send implements ResponseSender, applying the defaults for mt before
+// sending the response.
+func (w *defaultsSender) Send(mt dhcp6.MessageType) (int, error) {
+	w.applyDefaults(mt)
+	return w.ResponseSender.Send(mt)
+}
+
+// applyDefaults replaces the defaults previously applied to the response
+// with the defaults for mt.  Previous defaults which have been modified, and
+// options which are already set, are not changed.
+func (w *defaultsSender) applyDefaults(mt dhcp6.MessageType) {
+	if mt == w.mt {
+		return
+	}
+
+	o := w.Options()
+	for code, values := range w.defaults[w.mt] {
+		if equalValues(o[code], values) {
+			delete(o, code)
+		}
+	}
+
+	for code, values := range w.defaults[mt] {
+		if _, ok := o[code]; ok {
+			continue
+		}
+
+		o[code] = copyValues(values)
+	}
+
+	w.mt = mt
+}
+
+// equalValues reports whether a and b contain the same option values.
+func equalValues(a, b [][]byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if !bytes.Equal(a[i], b[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// copyValues returns a copy of the option values in vv.
+func copyValues(vv [][]byte) [][]byte {
+	c := make([][]byte, 0, len(vv))
+	for _, v := range vv {
+		c = append(c, append([]byte(nil), v...))
+	}
+
+	return c
+}
@@ -1,9 +1,12 @@
 package dhcp6server_test
 
 import (
+	"net"
+	"reflect"
 	"testing"
 
 	"github.com/mdlayher/dhcp6"
+	"github.com/mdlayher/dhcp6/dhcp6opts"
 	"github.com/mdlayher/dhcp6/dhcp6server"
 	"github.com/mdlayher/dhcp6/dhcp6test"
 )
@@ -74,6 +77,319 @@ func TestServeMuxHandleFuncOK(t *testing.T) {
 
 // solicitHandler is a Handler which returns an Advertise in reply
 // to a Solicit request.
+// TestServeMuxHandleDefault verifies that a ServeMux invokes its default
+// Handler only when no Handler is registered for a given message type.
+func TestServeMuxHandleDefault(t *testing.T) {
+	mux := dhcp6server.NewServeMux()
+	mux.HandleFunc(dhcp6.MessageTypeSolicit, solicit)
+	mux.HandleFuncDefault(func(w dhcp6server.ResponseSender, r *dhcp6server.Request) {
+		w.Send(dhcp6.MessageTypeReply)
+	})
+
+	var tests = []struct {
+		desc string
+		mt   dhcp6.MessageType
+		want dhcp6.MessageType
+	}{
+		{
+			desc: "registered message type",
+			mt:   dhcp6.MessageTypeSolicit,
+			want: dhcp6.MessageTypeAdvertise,
+		},
+		{
+			desc: "unregistered message type",
+			mt:   dhcp6.MessageTypeRenew,
+			want: dhcp6.MessageTypeReply,
+		},
+	}
+
+	for i, tt := range tests {
+		r, err := dhcp6server.ParseRequest([]byte{byte(tt.mt), 0, 1, 2}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		w := dhcp6test.NewRecorder(r.TransactionID)
+		mux.ServeDHCP(w, r)
+
+		if want, got := tt.want, w.MessageType; want != got {
+			t.Fatalf("[%02d] test %q, unexpected response message type: %v != %v",
+				i, tt.desc, want, got)
+		}
+	}
+}
+
+// TestServeMuxHandleDefaultNil verifies that registering a nil default
+// Handler removes the default, so unmatched requests are ignored.
+func TestServeMuxHandleDefaultNil(t *testing.T) {
+	mux := dhcp6server.NewServeMux()
+	mux.HandleDefault(&solicitHandler{})
+	mux.HandleDefault(nil)
+
+	r, err := dhcp6server.ParseRequest([]byte{1, 1, 2, 3}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := dhcp6test.NewRecorder(r.TransactionID)
+	mux.ServeDHCP(w, r)
+
+	if mt := w.MessageType; mt != dhcp6.MessageType(0) {
+		t.Fatalf("reply packet empty, but got message type: %v", mt)
+	}
+}
+
+// TestServeMuxSetDefaultOptions verifies that a ServeMux adds default
+// options to a response before invoking a Handler, that the Handler can
+// override or remove them, and that the defaults for the message type
+// actually sent are used.
+func TestServeMuxSetDefaultOptions(t *testing.T) {
+	defaultDNS := []net.IP{net.ParseIP("2001:db8::53")}
+	handlerDNS := []net.IP{net.ParseIP("2001:db8::54")}
+
+	replyDefaults := make(dhcp6.Options)
+	if err := dhcp6opts.SetDNSServers(replyDefaults, defaultDNS); err != nil {
+		t.Fatal(err)
+	}
+
+	advertiseDefaults := make(dhcp6.Options)
+	if err := advertiseDefaults.Add(dhcp6.OptionPreference, dhcp6opts.Preference(255)); err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = []struct {
+		desc     string
+		mt       dhcp6.MessageType
+		rapid    bool
+		send     dhcp6.MessageType
+		override bool
+		remove   bool
+		seen     []net.IP
+		dns      []net.IP
+		pref     bool
+	}{
+		{
+			desc: "default added to reply",
+			mt:   dhcp6.MessageTypeRequest,
+			send: dhcp6.MessageTypeReply,
+			seen: defaultDNS,
+			dns:  defaultDNS,
+		},
+		{
+			desc:     "handler overrides default",
+			mt:       dhcp6.MessageTypeRenew,
+			send:     dhcp6.MessageTypeReply,
+			override: true,
+			seen:     defaultDNS,
+			dns:      handlerDNS,
+		},
+		{
+			desc:   "handler removes default",
+			mt:     dhcp6.MessageTypeInformationRequest,
+			send:   dhcp6.MessageTypeReply,
+			remove: true,
+			seen:   defaultDNS,
+		},
+		{
+			desc: "advertise defaults for solicit",
+			mt:   dhcp6.MessageTypeSolicit,
+			send: dhcp6.MessageTypeAdvertise,
+			pref: true,
+		},
+		{
+			desc:  "rapid commit reply to solicit",
+			mt:    dhcp6.MessageTypeSolicit,
+			rapid: true,
+			send:  dhcp6.MessageTypeReply,
+			seen:  defaultDNS,
+			dns:   defaultDNS,
+		},
+		{
+			desc: "reply to solicit without rapid commit",
+			mt:   dhcp6.MessageTypeSolicit,
+			send: dhcp6.MessageTypeReply,
+			dns:  defaultDNS,
+		},
+	}
+
+	for i, tt := range tests {
+		var seen []net.IP
+
+		mux := dhcp6server.NewServeMux()
+		mux.SetDefaultOptions(dhcp6.MessageTypeReply, replyDefaults)
+		mux.SetDefaultOptions(dhcp6.MessageTypeAdvertise, advertiseDefaults)
+		mux.HandleFunc(tt.mt, func(w dhcp6server.ResponseSender, r *dhcp6server.Request) {
+			if tt.rapid && !dhcp6server.HonorRapidCommit(w, r, true) {
+				t.Fatalf("[%02d] test %q, rapid commit not honored", i, tt.desc)
+			}
+
+			if dns, err := dhcp6opts.GetDNSServers(w.Options()); err == nil {
+				seen = dns
+			}
+
+			if tt.override {
+				_ = dhcp6opts.SetDNSServers(w.Options(), handlerDNS)
+			}
+			if tt.remove {
+				w.Options().Del(dhcp6.OptionDNSServers)
+			}
+			w.Send(tt.send)
+		})
+
+		b := []byte{byte(tt.mt), 0, 1, 2}
+		if tt.rapid {
+			b = append(b, 0, byte(dhcp6.OptionRapidCommit), 0, 0)
+		}
+
+		r, err := dhcp6server.ParseRequest(b, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		w := dhcp6test.NewRecorder(r.TransactionID)
+		mux.ServeDHCP(w, r)
+
+		if want, got := tt.seen, seen; !reflect.DeepEqual(want, got) {
+			t.Fatalf("[%02d] test %q, unexpected DNS servers seen by handler: %v != %v",
+				i, tt.desc, want, got)
+		}
+
+		_, err = dhcp6opts.GetPreference(w.Options())
+		if want, got := tt.pref, err == nil; want != got {
+			t.Fatalf("[%02d] test %q, unexpected Preference option presence: %v != %v",
+				i, tt.desc, want, got)
+		}
+
+		dns, err := dhcp6opts.GetDNSServers(w.Options())
+		if tt.dns == nil {
+			if err != dhcp6.ErrOptionNotPresent {
+				t.Fatalf("[%02d] test %q, unexpected DNS servers: %v", i, tt.desc, dns)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if want, got := tt.dns, []net.IP(dns); !reflect.DeepEqual(want, got) {
+			t.Fatalf("[%02d] test %q, unexpected DNS servers: %v != %v",
+				i, tt.desc, want, got)
+		}
+	}
+}
+
+// TestServeMuxSetDefaultOptionsCopied verifies that ServeMux.SetDefaultOptions
+// copies its input, that a response's copy of the defaults can be modified
+// without affecting them, and that an empty Options map removes the defaults.
+func TestServeMuxSetDefaultOptionsCopied(t *testing.T) {
+	o := dhcp6.Options{
+		dhcp6.OptionPreference: [][]byte{{255}},
+	}
+
+	mux := dhcp6server.NewServeMux()
+	mux.SetDefaultOptions(dhcp6.MessageTypeReply, o)
+	var modify bool
+	mux.HandleFunc(dhcp6.MessageTypeRenew, func(w dhcp6server.ResponseSender, r *dhcp6server.Request) {
+		// Modifying a response must not affect the defaults.
+		if modify {
+			w.Options()[dhcp6.OptionPreference][0][0] = 0
+		}
+
+		w.Send(dhcp6.MessageTypeReply)
+	})
+
+	// Modifying the input must not affect the defaults.
+	o[dhcp6.OptionPreference][0][0] = 0
+
+	serve := func() dhcp6.Options {
+		r, err := dhcp6server.ParseRequest([]byte{byte(dhcp6.MessageTypeRenew), 0, 1, 2}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		w := dhcp6test.NewRecorder(r.TransactionID)
+		mux.ServeDHCP(w, r)
+		return w.Options()
+	}
+
+	pref, err := dhcp6opts.GetPreference(serve())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := dhcp6opts.Preference(255), pref; want != got {
+		t.Fatalf("unexpected preference: %v != %v", want, got)
+	}
+
+	// Modify a response, and serve again to verify the defaults are
+	// unaffected.
+	modify = true
+	_ = serve()
+	modify = false
+
+	pref, err = dhcp6opts.GetPreference(serve())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := dhcp6opts.Preference(255), pref; want != got {
+		t.Fatalf("unexpected preference after modifying response: %v != %v", want, got)
+	}
+
+	mux.SetDefaultOptions(dhcp6.MessageTypeReply, nil)
+	if l := len(serve()); l > 0 {
+		t.Fatalf("defaults removed, but got %d options", l)
+	}
+}
+
+// TestServeMuxStrict verifies that a strict ServeMux only panics when a
+// Handler is registered for a server-originated message type.
+func TestServeMuxStrict(t *testing.T) {
+	var tests = []struct {
+		desc   string
+		strict bool
+		mt     dhcp6.MessageType
+		panics bool
+	}{
+		{
+			desc: "permissive, client-originated type",
+			mt:   dhcp6.MessageTypeSolicit,
+		},
+		{
+			desc: "permissive, server-originated type",
+			mt:   dhcp6.MessageTypeReply,
+		},
+		{
+			desc:   "strict, client-originated type",
+			strict: true,
+			mt:     dhcp6.MessageTypeSolicit,
+		},
+		{
+			desc:   "strict, server-originated type",
+			strict: true,
+			mt:     dhcp6.MessageTypeAdvertise,
+			panics: true,
+		},
+	}
+
+	for i, tt := range tests {
+		mux := dhcp6server.NewServeMux()
+		mux.Strict = tt.strict
+
+		panicked := func() (panicked bool) {
+			defer func() {
+				panicked = recover() != nil
+			}()
+
+			mux.HandleFunc(tt.mt, solicit)
+			return false
+		}()
+
+		if want, got := tt.panics, panicked; want != got {
+			t.Fatalf("[%02d] test %q, unexpected panic: %v != %v",
+				i, tt.desc, want, got)
+		}
+	}
+}
+
 type solicitHandler struct{}
 
 func (h *solicitHandler) ServeDHCP(w dhcp6server.ResponseSender, r *dhcp6server.Request) {
@@ -2,8 +2,10 @@ package dhcp6server
 
 import (
 	"net"
+	"time"
 
 	"github.com/mdlayher/dhcp6"
+	"github.com/mdlayher/dhcp6/dhcp6opts"
 )
 
 // Request represents a processed DHCP request received by a server.
@@ -28,18 +30,190 @@ type Request struct {
 	// Length of the DHCP request, in bytes.
 	Length int64
 
+	// Truncated reports whether the datagram which carried the request
+	// filled the server's entire read buffer, and so may have been
+	// truncated.  Handlers may refuse to process a truncated request, as
+	// some of its options may be missing.  See Server.ReadBufferSize.
+	Truncated bool
+
 	// Network address which was used to contact the DHCP server.
 	RemoteAddr string
+
+	// Relay-forward messages which encapsulated the request, if it was
+	// received from a DHCP relay agent.  The first element is the outermost
+	// message received by the server, and the last element was sent by
+	// the relay agent closest to the client.  Relays is nil if the request
+	// was received directly from a client.
+	Relays []*dhcp6opts.RelayMessage
+
+	// dst is the destination address of the datagram which carried the
+	// request, if known.
+	dst net.IP
+}
+
+// MulticastGroup returns the IPv6 multicast group to which the request was
+// addressed, such as AllRelayAgentsAndServersAddr or AllServersAddr.  A
+// server can use this to apply different processing to requests received
+// on each group.
+//
+// If the request was sent to a unicast address, or the destination address
+// is not known, MulticastGroup returns false.
+func (r *Request) MulticastGroup() (net.IP, bool) {
+	if r.dst == nil || !r.dst.IsMulticast() {
+		return nil, false
+	}
+
+	return r.dst, true
+}
+
+// LinkAddress returns the link-address set by the relay agent closest to the
+// client, which a server can use to select an address pool for the link on
+// which the client is located, as described in RFC 3315, Section 11.
+//
+// If the request was not relayed, or no relay agent set a link-address,
+// LinkAddress returns false.
+func (r *Request) LinkAddress() (net.IP, bool) {
+	for i := len(r.Relays) - 1; i >= 0; i-- {
+		ip := r.Relays[i].LinkAddress
+		if ip != nil && !ip.IsUnspecified() {
+			return ip, true
+		}
+	}
+
+	return nil, false
+}
+
+// RelaySuppliedOptions returns the options supplied by relay agents in
+// Relay-Supplied Options options, as described in RFC 6422, which a server
+// may include in its response to the client.
+//
+// Only options whose codes appear in allowed are returned, since a server
+// must ignore any option which it is not configured to accept from a relay
+// agent.  If more than one relay agent supplies the same option, the values
+// from the relay agent closest to the client are used.  Malformed RSOO
+// options are ignored.  Options which a server supplies itself should take
+// precedence over those returned by RelaySuppliedOptions.
+//
+// If the request was not relayed, or no allowed options were supplied,
+// RelaySuppliedOptions returns an empty Options map.
+func (r *Request) RelaySuppliedOptions(allowed ...dhcp6.OptionCode) dhcp6.Options {
+	o := make(dhcp6.Options)
+	for i := len(r.Relays) - 1; i >= 0; i-- {
+		rsoo, err := dhcp6opts.GetRelaySuppliedOptions(r.Relays[i].Options)
+		if err != nil {
+			continue
+		}
+
+		for _, code := range allowed {
+			vv, ok := rsoo[code]
+			if !ok {
+				continue
+			}
+			if _, ok := o[code]; ok {
+				continue
+			}
+
+			o[code] = vv
+		}
+	}
+
+	return o
+}
+
+// ClientEnterpriseNumber returns the vendor's IANA Private Enterprise Number
+// from the client's DUID, which a server can use to apply vendor-specific
+// policy.
+//
+// If the request has no valid Client Identifier option, or the client's DUID
+// is not a DUID-EN, ClientEnterpriseNumber returns false.
+func (r *Request) ClientEnterpriseNumber() (uint32, bool) {
+	duid, err := dhcp6opts.GetClientID(r.Options)
+	if err != nil {
+		return 0, false
+	}
+
+	en, ok := duid.(*dhcp6opts.DUIDEN)
+	if !ok {
+		return 0, false
+	}
+
+	return en.EnterpriseNumber, true
+}
+
+// ElapsedTime returns the time elapsed since the client began its current
+// DHCP transaction, from the Elapsed Time option described in RFC 3315,
+// Section 22.9.
+//
+// A client which has retransmitted a request for some time reports a larger
+// elapsed time than one which has just started, so a server under load may
+// use it to prioritize clients which have been waiting longest, or a
+// secondary server may use it to answer only clients whose primary server
+// has not responded in time.
+//
+// If the request has no valid Elapsed Time option, ElapsedTime returns false.
+func (r *Request) ElapsedTime() (time.Duration, bool) {
+	et, err := dhcp6opts.GetElapsedTime(r.Options)
+	if err != nil {
+		return 0, false
+	}
+
+	return time.Duration(et), true
+}
+
+// RapidCommit reports whether the client requested the two message exchange
+// for address assignment, by including a valid Rapid Commit option, as
+// described in RFC 3315, Section 22.14.
+func (r *Request) RapidCommit() bool {
+	return dhcp6opts.GetRapidCommit(r.Options) == nil
+}
+
+// HonorRapidCommit reports whether a server should answer a Solicit using
+// the two message exchange, by sending a Reply rather than an Advertise, as
+// described in RFC 3315, Section 17.2.3.  This is the case only when the
+// request is a Solicit which includes a Rapid Commit option, and allow
+// indicates the server is configured to permit rapid commit.
+//
+// If HonorRapidCommit returns true, a Rapid Commit option has been added to
+// w's Options, as required in the Reply.  If w was provided by a ServeMux
+// with default options, the Advertise defaults in w's Options are replaced
+// by the Reply defaults.
+func HonorRapidCommit(w ResponseSender, r *Request, allow bool) bool {
+	if !allow || r.MessageType != dhcp6.MessageTypeSolicit || !r.RapidCommit() {
+		return false
+	}
+
+	if ds, ok := w.(*defaultsSender); ok {
+		ds.applyDefaults(dhcp6.MessageTypeReply)
+	}
+
+	o := w.Options()
+	if _, ok := o[dhcp6.OptionRapidCommit]; !ok {
+		_ = o.Add(dhcp6.OptionRapidCommit, nil)
+	}
+
+	return true
+}
+
+// WillAcceptReconfigure reports whether the client indicated that it will
+// accept Reconfigure messages, by including a valid Reconfigure Accept
+// option, as described in RFC 3315, Section 22.20.  A server must not send
+// Reconfigure messages to a client which does not include this option.
+func (r *Request) WillAcceptReconfigure() bool {
+	return dhcp6opts.GetReconfigureAccept(r.Options) == nil
 }
 
 // ParseRequest creates a new Request from an input byte slice and UDP address.
 // It populates the basic struct members which can be used in a DHCP handler.
 //
+// If the input byte slice contains a Relay-forward message, the client
+// message encapsulated within it is used to populate the Request, and the
+// Relay-forward messages are stored in Relays.
+//
 // If the input byte slice is not a valid DHCP packet, ErrInvalidPacket is
 // returned.
 func ParseRequest(b []byte, remoteAddr *net.UDPAddr) (*Request, error) {
-	p := new(dhcp6.Packet)
-	if err := p.UnmarshalBinary(b); err != nil {
+	p, relays, err := parsePacket(b)
+	if err != nil {
 		return nil, err
 	}
 
@@ -49,5 +223,26 @@ func ParseRequest(b []byte, remoteAddr *net.UDPAddr) (*Request, error) {
 		Options:       p.Options,
 		Length:        int64(len(b)),
 		RemoteAddr:    remoteAddr.String(),
+		Relays:        relays,
 	}, nil
 }
+
+// parsePacket parses the client message in b, unwrapping it from any
+// Relay-forward messages, which are returned in order.
+func parsePacket(b []byte) (*dhcp6.Packet, []*dhcp6opts.RelayMessage, error) {
+	if len(b) == 0 || dhcp6.MessageType(b[0]) != dhcp6.MessageTypeRelayForw {
+		p := new(dhcp6.Packet)
+		if err := p.UnmarshalBinary(b); err != nil {
+			return nil, nil, err
+		}
+
+		return p, nil, nil
+	}
+
+	rm := new(dhcp6opts.RelayMessage)
+	if err := rm.UnmarshalBinary(b); err != nil {
+		return nil, nil, dhcp6.ErrInvalidPacket
+	}
+
+	return rm.Decapsulate()
+}
@@ -4,9 +4,11 @@ import (
 	"net"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/mdlayher/dhcp6"
 	"github.com/mdlayher/dhcp6/dhcp6opts"
+	"github.com/mdlayher/dhcp6/dhcp6test"
 )
 
 // TestParseRequest verifies that ParseRequest returns a consistent
@@ -49,3 +51,488 @@ func TestParseRequest(t *testing.T) {
 			p, addr, want, got)
 	}
 }
+
+// TestParseRequestRelayed verifies that ParseRequest unwraps a client message
+// encapsulated in a Relay-forward message, and that Request.LinkAddress
+// reports the relay agent's link-address.
+func TestParseRequestRelayed(t *testing.T) {
+	p := &dhcp6.Packet{
+		MessageType:   dhcp6.MessageTypeSolicit,
+		TransactionID: [3]byte{1, 2, 3},
+		Options:       make(dhcp6.Options),
+	}
+
+	var rmo dhcp6opts.RelayMessageOption
+	if err := rmo.SetClientServerMessage(p); err != nil {
+		t.Fatal(err)
+	}
+
+	link := net.ParseIP("2001:db8::1")
+	rm := &dhcp6opts.RelayMessage{
+		MessageType: dhcp6.MessageTypeRelayForw,
+		LinkAddress: link,
+		PeerAddress: net.ParseIP("fe80::1"),
+		Options:     make(dhcp6.Options),
+	}
+	rm.Options.Add(dhcp6.OptionRelayMsg, &rmo)
+
+	buf, err := rm.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := ParseRequest(buf, &net.UDPAddr{
+		IP:   net.ParseIP("2001:db8::2"),
+		Port: 547,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := p.MessageType, r.MessageType; want != got {
+		t.Fatalf("unexpected message type: %v != %v", want, got)
+	}
+	if want, got := p.TransactionID, r.TransactionID; want != got {
+		t.Fatalf("unexpected transaction ID: %v != %v", want, got)
+	}
+	if want, got := 1, len(r.Relays); want != got {
+		t.Fatalf("unexpected number of relays: %v != %v", want, got)
+	}
+
+	ip, ok := r.LinkAddress()
+	if !ok {
+		t.Fatal("link-address not found for relayed request")
+	}
+	if want, got := link, ip; !want.Equal(got) {
+		t.Fatalf("unexpected link-address: %v != %v", want, got)
+	}
+}
+
+// TestParseRequestCopiesBuffer verifies that a Request's options do not refer
+// to the input buffer, so that a Server may reuse the buffer once a request
+// has been served.
+func TestParseRequestCopiesBuffer(t *testing.T) {
+	p := &dhcp6.Packet{
+		MessageType:   dhcp6.MessageTypeSolicit,
+		TransactionID: [3]byte{1, 2, 3},
+		Options:       make(dhcp6.Options),
+	}
+	p.Options.AddRaw(dhcp6.OptionClientID, []byte{0, 1, 2, 3})
+
+	buf, err := p.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := ParseRequest(buf, &net.UDPAddr{
+		IP:   net.ParseIP("::1"),
+		Port: 546,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Overwrite the buffer, as a Server would when reading the next request.
+	for i := range buf {
+		buf[i] = 0xff
+	}
+
+	v, err := r.Options.GetOne(dhcp6.OptionClientID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := []byte{0, 1, 2, 3}, v; !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected client ID after reusing buffer: %v != %v", want, got)
+	}
+}
+
+// TestRequestLinkAddressNotRelayed verifies that Request.LinkAddress reports
+// no link-address for a request which was not relayed.
+func TestRequestLinkAddressNotRelayed(t *testing.T) {
+	r, err := ParseRequest([]byte{1, 1, 2, 3}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ip, ok := r.LinkAddress(); ok {
+		t.Fatalf("unexpected link-address for request which was not relayed: %v", ip)
+	}
+}
+
+// TestRequestRelaySuppliedOptions verifies that Request.RelaySuppliedOptions
+// returns only allowed options, preferring those from the relay agent closest
+// to the client.
+func TestRequestRelaySuppliedOptions(t *testing.T) {
+	relay := func(rsoo dhcp6.Options) *dhcp6opts.RelayMessage {
+		o := make(dhcp6.Options)
+		if rsoo != nil {
+			if err := dhcp6opts.SetRelaySuppliedOptions(o, rsoo); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		return &dhcp6opts.RelayMessage{
+			MessageType: dhcp6.MessageTypeRelayForw,
+			Options:     o,
+		}
+	}
+
+	outer := make(dhcp6.Options)
+	if err := dhcp6opts.SetDNSServers(outer, []net.IP{net.ParseIP("2001:db8::1")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := outer.Add(dhcp6.OptionPreference, dhcp6opts.Preference(255)); err != nil {
+		t.Fatal(err)
+	}
+
+	inner := make(dhcp6.Options)
+	if err := dhcp6opts.SetDNSServers(inner, []net.IP{net.ParseIP("2001:db8::2")}); err != nil {
+		t.Fatal(err)
+	}
+
+	malformed := &dhcp6opts.RelayMessage{
+		MessageType: dhcp6.MessageTypeRelayForw,
+		Options: dhcp6.Options{
+			dhcp6.OptionRSOO: [][]byte{{0, 23, 0, 16}},
+		},
+	}
+
+	var tests = []struct {
+		desc    string
+		relays  []*dhcp6opts.RelayMessage
+		allowed []dhcp6.OptionCode
+		o       dhcp6.Options
+	}{
+		{
+			desc:    "not relayed",
+			allowed: []dhcp6.OptionCode{dhcp6.OptionDNSServers},
+			o:       dhcp6.Options{},
+		},
+		{
+			desc:   "no options allowed",
+			relays: []*dhcp6opts.RelayMessage{relay(outer)},
+			o:      dhcp6.Options{},
+		},
+		{
+			desc:    "only allowed options",
+			relays:  []*dhcp6opts.RelayMessage{relay(outer), relay(nil), malformed},
+			allowed: []dhcp6.OptionCode{dhcp6.OptionDNSServers},
+			o: dhcp6.Options{
+				dhcp6.OptionDNSServers: outer[dhcp6.OptionDNSServers],
+			},
+		},
+		{
+			desc:    "closest relay agent takes precedence",
+			relays:  []*dhcp6opts.RelayMessage{relay(outer), relay(inner)},
+			allowed: []dhcp6.OptionCode{dhcp6.OptionDNSServers, dhcp6.OptionPreference},
+			o: dhcp6.Options{
+				dhcp6.OptionDNSServers: inner[dhcp6.OptionDNSServers],
+				dhcp6.OptionPreference: outer[dhcp6.OptionPreference],
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		r := &Request{
+			Relays: tt.relays,
+		}
+
+		if want, got := tt.o, r.RelaySuppliedOptions(tt.allowed...); !reflect.DeepEqual(want, got) {
+			t.Fatalf("[%02d] test %q, unexpected relay-supplied options:\n- want: %v\n-  got: %v",
+				i, tt.desc, want, got)
+		}
+	}
+}
+
+// TestParseRequestRelayedMissingRelayMessage verifies that ParseRequest
+// rejects a Relay-forward message with no Relay Message option.
+func TestParseRequestRelayedMissingRelayMessage(t *testing.T) {
+	rm := &dhcp6opts.RelayMessage{
+		MessageType: dhcp6.MessageTypeRelayForw,
+	}
+
+	buf, err := rm.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ParseRequest(buf, nil); err != dhcp6.ErrInvalidPacket {
+		t.Fatalf("unexpected error: %v != %v", dhcp6.ErrInvalidPacket, err)
+	}
+}
+
+// TestRequestMulticastGroup verifies that Request.MulticastGroup only reports
+// a group for requests sent to a multicast address.
+func TestRequestMulticastGroup(t *testing.T) {
+	var tests = []struct {
+		desc string
+		dst  net.IP
+		ok   bool
+	}{
+		{
+			desc: "destination unknown",
+		},
+		{
+			desc: "unicast destination",
+			dst:  net.ParseIP("2001:db8::1"),
+		},
+		{
+			desc: "all DHCP relay agents and servers",
+			dst:  AllRelayAgentsAndServersAddr.IP,
+			ok:   true,
+		},
+		{
+			desc: "all DHCP servers",
+			dst:  AllServersAddr.IP,
+			ok:   true,
+		},
+	}
+
+	for i, tt := range tests {
+		r := &Request{
+			dst: tt.dst,
+		}
+
+		group, ok := r.MulticastGroup()
+		if want, got := tt.ok, ok; want != got {
+			t.Errorf("[%02d] test %q, unexpected value for Request.MulticastGroup(): %v != %v",
+				i, tt.desc, want, got)
+			continue
+		}
+		if ok && !tt.dst.Equal(group) {
+			t.Errorf("[%02d] test %q, unexpected multicast group: %v != %v",
+				i, tt.desc, tt.dst, group)
+		}
+	}
+}
+
+// TestRequestClientEnterpriseNumber verifies that
+// Request.ClientEnterpriseNumber only returns an enterprise number for
+// clients which use a DUID-EN.
+func TestRequestClientEnterpriseNumber(t *testing.T) {
+	var tests = []struct {
+		desc   string
+		duid   dhcp6opts.DUID
+		number uint32
+		ok     bool
+	}{
+		{
+			desc: "no client ID",
+		},
+		{
+			desc: "DUID-LL client ID",
+			duid: dhcp6opts.NewDUIDLL(1, net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad}),
+		},
+		{
+			desc:   "DUID-EN client ID",
+			duid:   dhcp6opts.NewDUIDEN(32473, []byte{0xde, 0xad, 0xbe, 0xef}),
+			number: 32473,
+			ok:     true,
+		},
+	}
+
+	for i, tt := range tests {
+		r := &Request{
+			Options: make(dhcp6.Options),
+		}
+		if tt.duid != nil {
+			if err := r.Options.Add(dhcp6.OptionClientID, tt.duid); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		number, ok := r.ClientEnterpriseNumber()
+		if want, got := tt.ok, ok; want != got {
+			t.Errorf("[%02d] test %q, unexpected value for Request.ClientEnterpriseNumber(): %v != %v",
+				i, tt.desc, want, got)
+			continue
+		}
+		if want, got := tt.number, number; want != got {
+			t.Errorf("[%02d] test %q, unexpected enterprise number: %v != %v",
+				i, tt.desc, want, got)
+		}
+	}
+}
+
+// TestRequestElapsedTime verifies that Request.ElapsedTime reports the
+// elapsed time from a valid Elapsed Time option.
+func TestRequestElapsedTime(t *testing.T) {
+	var tests = []struct {
+		desc    string
+		options dhcp6.Options
+		elapsed time.Duration
+		ok      bool
+	}{
+		{
+			desc: "no elapsed time",
+		},
+		{
+			desc: "malformed elapsed time",
+			options: dhcp6.Options{
+				dhcp6.OptionElapsedTime: [][]byte{{1}},
+			},
+		},
+		{
+			desc: "elapsed time present",
+			options: dhcp6.Options{
+				dhcp6.OptionElapsedTime: [][]byte{{0x01, 0xf4}},
+			},
+			elapsed: 5 * time.Second,
+			ok:      true,
+		},
+	}
+
+	for i, tt := range tests {
+		r := &Request{
+			Options: tt.options,
+		}
+
+		elapsed, ok := r.ElapsedTime()
+		if want, got := tt.ok, ok; want != got {
+			t.Errorf("[%02d] test %q, unexpected value for Request.ElapsedTime(): %v != %v",
+				i, tt.desc, want, got)
+			continue
+		}
+		if want, got := tt.elapsed, elapsed; want != got {
+			t.Errorf("[%02d] test %q, unexpected elapsed time: %v != %v",
+				i, tt.desc, want, got)
+		}
+	}
+}
+
+// TestRequestWillAcceptReconfigure verifies that
+// Request.WillAcceptReconfigure reports whether a client included a valid
+// Reconfigure Accept option.
+func TestRequestWillAcceptReconfigure(t *testing.T) {
+	var tests = []struct {
+		desc    string
+		options dhcp6.Options
+		ok      bool
+	}{
+		{
+			desc: "Reconfigure Accept absent",
+		},
+		{
+			desc: "Reconfigure Accept present",
+			options: dhcp6.Options{
+				dhcp6.OptionReconfAccept: [][]byte{{}},
+			},
+			ok: true,
+		},
+		{
+			desc: "Reconfigure Accept present, but non-empty",
+			options: dhcp6.Options{
+				dhcp6.OptionReconfAccept: [][]byte{{1}},
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		r := &Request{
+			Options: tt.options,
+		}
+
+		if want, got := tt.ok, r.WillAcceptReconfigure(); want != got {
+			t.Errorf("[%02d] test %q, unexpected value for Request.WillAcceptReconfigure(): %v != %v",
+				i, tt.desc, want, got)
+		}
+	}
+}
+
+// TestRequestRapidCommit verifies that Request.RapidCommit only reports true
+// when a valid Rapid Commit option is present.
+func TestRequestRapidCommit(t *testing.T) {
+	var tests = []struct {
+		desc    string
+		options dhcp6.Options
+		ok      bool
+	}{
+		{
+			desc: "Rapid Commit absent",
+		},
+		{
+			desc: "Rapid Commit present",
+			options: dhcp6.Options{
+				dhcp6.OptionRapidCommit: [][]byte{{}},
+			},
+			ok: true,
+		},
+		{
+			desc: "Rapid Commit present, but non-empty",
+			options: dhcp6.Options{
+				dhcp6.OptionRapidCommit: [][]byte{{1}},
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		r := &Request{
+			Options: tt.options,
+		}
+
+		if want, got := tt.ok, r.RapidCommit(); want != got {
+			t.Errorf("[%02d] test %q, unexpected value for Request.RapidCommit(): %v != %v",
+				i, tt.desc, want, got)
+		}
+	}
+}
+
+// TestHonorRapidCommit verifies that HonorRapidCommit only honors Rapid
+// Commit for Solicit messages which request it, when the server allows it,
+// and that it adds a Rapid Commit option to the response.
+func TestHonorRapidCommit(t *testing.T) {
+	rapid := dhcp6.Options{
+		dhcp6.OptionRapidCommit: [][]byte{{}},
+	}
+
+	var tests = []struct {
+		desc    string
+		mt      dhcp6.MessageType
+		options dhcp6.Options
+		allow   bool
+		ok      bool
+	}{
+		{
+			desc:    "requested and allowed",
+			mt:      dhcp6.MessageTypeSolicit,
+			options: rapid,
+			allow:   true,
+			ok:      true,
+		},
+		{
+			desc:    "requested and disallowed",
+			mt:      dhcp6.MessageTypeSolicit,
+			options: rapid,
+		},
+		{
+			desc:  "not requested",
+			mt:    dhcp6.MessageTypeSolicit,
+			allow: true,
+		},
+		{
+			desc:    "requested in a Request message",
+			mt:      dhcp6.MessageTypeRequest,
+			options: rapid,
+			allow:   true,
+		},
+	}
+
+	for i, tt := range tests {
+		r := &Request{
+			MessageType: tt.mt,
+			Options:     tt.options,
+		}
+		w := dhcp6test.NewRecorder(r.TransactionID)
+
+		if want, got := tt.ok, HonorRapidCommit(w, r, tt.allow); want != got {
+			t.Fatalf("[%02d] test %q, unexpected value for HonorRapidCommit: %v != %v",
+				i, tt.desc, want, got)
+		}
+
+		_, added := w.Options()[dhcp6.OptionRapidCommit]
+		if want, got := tt.ok, added; want != got {
+			t.Fatalf("[%02d] test %q, unexpected Rapid Commit option in response: %v != %v",
+				i, tt.desc, want, got)
+		}
+	}
+}
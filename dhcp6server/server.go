@@ -1,9 +1,18 @@
 package dhcp6server
 
 import (
+	"context"
+	"crypto/rand"
 	"errors"
+	"fmt"
+	"io/ioutil"
 	"log"
 	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/mdlayher/dhcp6"
 	"github.com/mdlayher/dhcp6/dhcp6opts"
@@ -28,11 +37,26 @@ var (
 		IP: net.ParseIP("ff05::1:3"),
 	}
 
+	// ErrUnicastInAdvertise is returned by a ResponseSender when a
+	// Server Unicast option is sent in an Advertise message.  As described
+	// in RFC 3315, Section 17.2.2, the Server Unicast option may only be
+	// sent in a Reply.
+	ErrUnicastInAdvertise = errors.New("server unicast option must not be sent in advertise")
+
+	// ErrServerRunning is returned by Server.ListenAndServe and Server.Serve
+	// when the Server is already serving DHCP traffic.  A Server may be
+	// started again once a previous call to either method has returned.
+	ErrServerRunning = errors.New("server already running")
+
 	// errClosing is a special value used to stop the server's read loop
 	// when a connection is closing.
 	errClosing = errors.New("use of closed network connection")
 )
 
+// defaultAddr is the default address a Server binds to, as specified in
+// RFC 3315, Section 5.2.
+const defaultAddr = "[::]:547"
+
 // PacketConn is an interface which types must implement in order to serve
 // DHCP connections using Server.Serve.
 type PacketConn interface {
@@ -57,6 +81,9 @@ type Server struct {
 
 	// Addr is the network address which this server should bind to.  The
 	// default value is [::]:547, as specified in RFC 3315, Section 5.2.
+	// A different port may be used for testing, or when running without
+	// the privileges needed to bind to port 547.  Port 0 binds to an
+	// ephemeral port chosen by the operating system.
 	Addr string
 
 	// Handler is the handler to use while serving DHCP requests.  If this
@@ -72,16 +99,201 @@ type Server struct {
 
 	// ServerID is the the server's DUID, which uniquely identifies this
 	// server to clients.  If no DUID is specified, a DUID-LL will be
-	// generated using Iface's hardware type and address.  If possible,
-	// servers with persistent storage available should generate a DUID-LLT
-	// and store it for future use.
+	// generated using Iface's hardware type and address.  If Iface has no
+	// hardware address, a DUID-UUID will be generated using a random UUID.
+	// If possible, servers with persistent storage available should generate
+	// a DUID-LLT and store it for future use, using PersistDUIDPath.  A
+	// DUID stored in a configuration file can be loaded using
+	// dhcp6opts.ParseDUID.
 	ServerID dhcp6opts.DUID
 
+	// PersistDUIDPath is an optional path to a file used to store the
+	// server's DUID.  If ServerID is nil and PersistDUIDPath is set, the
+	// DUID is loaded from the file.  If the file does not exist, a DUID-LLT
+	// is generated using Iface's hardware address and the current time, and
+	// stored in the file for future use, as recommended in RFC 3315,
+	// Section 9.2.
+	PersistDUIDPath string
+
+	// RejectZeroTransactionID specifies whether the server should drop
+	// requests with an all-zeros transaction ID.  Though such a transaction
+	// ID is valid, it often indicates a buggy client or a network scanner.
+	// By default, these requests are handled normally.
+	RejectZeroTransactionID bool
+
+	// ReplySourceAddr is an optional IPv6 address which is used as the
+	// source address for all replies sent by this server.  On links where
+	// the server has multiple addresses, this can be used to ensure replies
+	// are sent from a link-local or other known address.  If
+	// ReplySourceAddr is nil, the source address is chosen by the operating
+	// system.
+	ReplySourceAddr net.IP
+
+	// ReplyFromRequestAddr specifies whether replies to requests which were
+	// sent to one of the server's unicast addresses should be sent from that
+	// same address.  On interfaces with several IPv6 addresses, this ensures
+	// clients which contacted the server directly receive replies from the
+	// address they expect.  Replies to requests sent to a multicast group
+	// are unaffected.  ReplySourceAddr takes precedence if set.  By default,
+	// the source address is chosen by the operating system.
+	ReplyFromRequestAddr bool
+
+	// AllowUnicast specifies whether clients may send messages directly to
+	// this server's unicast address, as described in RFC 3315, Section
+	// 22.12.  If set, the server derives a global unicast or unique local
+	// address from Iface, and adds a Server Unicast option with that
+	// address to each response, other than Advertise messages and
+	// responses to relayed requests.  If Iface has no such address, no
+	// Server Unicast option is added.
+	//
+	// The Server Unicast option is added when a Handler calls Send.  A
+	// response which already contains an Authentication option, such as
+	// one signed using dhcp6opts.AuthSign, is never modified, so a Handler
+	// which signs its responses must add the Server Unicast option itself
+	// before signing.
+	AllowUnicast bool
+
+	// ReadBufferSize is the size, in bytes, of the buffer each request is
+	// read into.  Requests larger than the buffer are truncated, and will
+	// most likely fail to parse; those which do parse are marked using
+	// Request.Truncated.  If ReadBufferSize is zero, a size of 1500
+	// bytes, the MTU of an Ethernet link, is used.
+	ReadBufferSize int
+
 	// ErrorLog is an optional logger which can be used to report errors and
 	// erroneous behavior while the server is accepting client requests.
 	// If ErrorLog is nil, logging goes to os.Stderr via the log package's
 	// standard logger.
 	ErrorLog *log.Logger
+
+	// mu protects running, closing, conn, and joined.
+	mu      sync.Mutex
+	running bool
+	closing bool
+	conn    PacketConn
+	joined  []*net.IPAddr
+
+	// wg tracks goroutines which are serving requests.
+	wg sync.WaitGroup
+
+	// bufPool stores read buffers which may be reused once a request has
+	// been served.
+	bufPool sync.Pool
+
+	// unicast is the address sent in a Server Unicast option, derived from
+	// Iface when AllowUnicast is set.
+	unicast net.IP
+
+	// interfaceAddrs returns the addresses of an interface.  If nil,
+	// net.Interface.Addrs is used.  It is replaced in tests.
+	interfaceAddrs func(ifi *net.Interface) ([]net.Addr, error)
+}
+
+// defaultReadBufferSize is the default size of the buffers a Server reads
+// requests into.
+const defaultReadBufferSize = 1500
+
+// readBufferSize returns s.ReadBufferSize, or the default size if
+// s.ReadBufferSize is zero.
+func (s *Server) readBufferSize() int {
+	if s.ReadBufferSize <= 0 {
+		return defaultReadBufferSize
+	}
+
+	return s.ReadBufferSize
+}
+
+// getBuffer returns a read buffer from the server's pool, allocating a new
+// one if none of the configured size are available.
+func (s *Server) getBuffer() *[]byte {
+	size := s.readBufferSize()
+	if b, ok := s.bufPool.Get().(*[]byte); ok && len(*b) == size {
+		return b
+	}
+
+	b := make([]byte, size)
+	return &b
+}
+
+// putBuffer returns a read buffer to the server's pool.
+func (s *Server) putBuffer(b *[]byte) {
+	s.bufPool.Put(b)
+}
+
+// start marks the server as running, returning ErrServerRunning if it is
+// already running.
+func (s *Server) start() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.running {
+		return ErrServerRunning
+	}
+
+	s.running = true
+	return nil
+}
+
+// stop marks the server as no longer running.
+func (s *Server) stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.running = false
+}
+
+// Close stops a running server by leaving its joined multicast groups and
+// closing its connection, causing Serve or ListenAndServe to return nil once
+// any requests being served have been handled.  Close may be called
+// concurrently, and does nothing if the server is not serving.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		return nil
+	}
+
+	s.closing = true
+	return s.closeLocked()
+}
+
+// closeLocked leaves the server's joined multicast groups and closes its
+// connection.  s.mu must be held when calling closeLocked.
+func (s *Server) closeLocked() error {
+	if s.conn == nil {
+		return nil
+	}
+
+	for _, g := range s.joined {
+		_ = s.conn.LeaveGroup(s.Iface, g)
+	}
+	s.joined = nil
+
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+// isClosing reports whether Close has been called for the current
+// connection.
+func (s *Server) isClosing() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.closing
+}
+
+// JoinedGroups returns the IPv6 multicast groups this server has joined
+// while serving.  It returns an empty slice if the server is not serving,
+// or no groups have been joined.
+func (s *Server) JoinedGroups() []*net.IPAddr {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	groups := make([]*net.IPAddr, len(s.joined))
+	copy(groups, s.joined)
+	return groups
 }
 
 // logf logs a message using the server's ErrorLog logger, or the log package
@@ -113,7 +325,7 @@ func ListenAndServe(iface string, handler Handler) error {
 
 	return (&Server{
 		Iface:   ifi,
-		Addr:    "[::]:547",
+		Addr:    defaultAddr,
 		Handler: handler,
 		MulticastGroups: []*net.IPAddr{
 			AllRelayAgentsAndServersAddr,
@@ -126,15 +338,103 @@ func ListenAndServe(iface string, handler Handler) error {
 // interface defined in s.Iface.  Traffic from any other interface will be
 // filtered out and ignored.  Serve is called to handle serving DHCP traffic
 // once ListenAndServe opens a UDP6 packet connection.
+//
+// If s.Addr is not an IPv6 address and port, such as [::]:547 or
+// [fe80::1%eth0]:547, an error is returned before a socket is opened.
+//
+// ListenAndServe returns nil once Close is called.  If the server is already
+// running, ErrServerRunning is returned.
 func (s *Server) ListenAndServe() error {
+	addr := s.addr()
+	if err := validateAddr(addr); err != nil {
+		return err
+	}
+
+	if err := s.start(); err != nil {
+		return err
+	}
+	defer s.stop()
+
 	// Open UDP6 packet connection listener on specified address
-	conn, err := net.ListenPacket("udp6", s.Addr)
+	conn, err := net.ListenPacket("udp6", addr)
 	if err != nil {
 		return err
 	}
 
 	defer conn.Close()
-	return s.Serve(ipv6.NewPacketConn(conn))
+	return s.serve(context.Background(), ipv6.NewPacketConn(conn))
+}
+
+// Port returns the UDP port this server binds to, as specified by s.Addr.
+// If s.Addr is empty, the default port 547 is returned.  If s.Addr does not
+// contain a valid port, Port returns 0.
+func (s *Server) Port() int {
+	port, err := parsePort(s.addr())
+	if err != nil {
+		return 0
+	}
+
+	return port
+}
+
+// addr returns s.Addr, or the default address if s.Addr is empty.
+func (s *Server) addr() string {
+	if s.Addr == "" {
+		return defaultAddr
+	}
+
+	return s.Addr
+}
+
+// parsePort parses the UDP port from a host:port address.
+func parsePort(addr string) (int, error) {
+	_, p, err := net.SplitHostPort(addr)
+	if err != nil {
+		return 0, err
+	}
+
+	port, err := strconv.Atoi(p)
+	if err != nil || port < 0 || port > 65535 {
+		return 0, fmt.Errorf("invalid port %q in address %q", p, addr)
+	}
+
+	return port, nil
+}
+
+// validateAddr verifies that addr is a host:port address with a valid port,
+// and a host which is empty or a literal IPv6 address with an optional zone.
+func validateAddr(addr string) error {
+	if _, err := parsePort(addr); err != nil {
+		return err
+	}
+
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+
+	// An empty host binds to all addresses, as with [::].
+	if host == "" {
+		return nil
+	}
+
+	ip, zone := host, ""
+	if i := strings.LastIndexByte(host, '%'); i != -1 {
+		ip, zone = host[:i], host[i+1:]
+		if zone == "" {
+			return fmt.Errorf("empty IPv6 zone in address %q", addr)
+		}
+	}
+
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return fmt.Errorf("invalid IPv6 address %q in address %q", ip, addr)
+	}
+	if parsed.To4() != nil {
+		return fmt.Errorf("address %q is not an IPv6 address", addr)
+	}
+
+	return nil
 }
 
 // Serve configures and accepts incoming connections on PacketConn p, creating a
@@ -143,13 +443,54 @@ func (s *Server) ListenAndServe() error {
 //
 // The service goroutine reads requests, generate the appropriate Request and
 // ResponseSender values, then calls s.Handler to handle the request.
+//
+// Serve returns nil once Close is called.  If the server is already running,
+// ErrServerRunning is returned.
 func (s *Server) Serve(p PacketConn) error {
-	// If no DUID was set for server previously, generate a DUID-LL
-	// now using the interface's hardware address, and just assume the
-	// "Ethernet 10Mb" hardware type since the caller probably doesn't care.
+	return s.ServeContext(context.Background(), p)
+}
+
+// ServeContext is like Serve, but also stops serving when ctx is canceled,
+// returning ctx.Err().
+func (s *Server) ServeContext(ctx context.Context, p PacketConn) error {
+	if err := s.start(); err != nil {
+		return err
+	}
+	defer s.stop()
+
+	return s.serve(ctx, p)
+}
+
+// serve implements ServeContext, once the server has been marked as running.
+func (s *Server) serve(ctx context.Context, p PacketConn) error {
+	// If no DUID was set for server previously, load a persistent DUID or
+	// generate one now using the interface's hardware address.
 	if s.ServerID == nil {
-		const ethernet10Mb uint16 = 1
-		s.ServerID = dhcp6opts.NewDUIDLL(ethernet10Mb, s.Iface.HardwareAddr)
+		var duid dhcp6opts.DUID
+		var err error
+		if s.PersistDUIDPath != "" {
+			duid, err = persistentServerDUID(s.PersistDUIDPath, s.Iface)
+		} else {
+			duid, err = serverDUID(s.Iface)
+		}
+		if err != nil {
+			return err
+		}
+		s.ServerID = duid
+	}
+
+	// Determine the address clients may send messages to directly,
+	// if permitted.
+	s.unicast = nil
+	if s.AllowUnicast {
+		ip, err := s.unicastAddr()
+		if err != nil {
+			return err
+		}
+		if ip == nil {
+			s.logf("no global unicast address on interface; Server Unicast option will not be sent")
+		}
+		s.unicast = ip
 	}
 
 	// Filter any traffic which does not indicate the interface
@@ -158,54 +499,273 @@ func (s *Server) Serve(p PacketConn) error {
 		return err
 	}
 
+	// Report the destination address of incoming traffic, so handlers
+	// can determine which multicast group a request was sent to.
+	if err := p.SetControlMessage(ipv6.FlagDst, true); err != nil {
+		return err
+	}
+
 	// Join appropriate multicast groups
 	for _, g := range s.MulticastGroups {
 		if err := p.JoinGroup(s.Iface, g); err != nil {
+			// Leave any groups joined before the failure, since the
+			// cleanup below has not yet been deferred
+			s.mu.Lock()
+			for _, j := range s.joined {
+				_ = p.LeaveGroup(s.Iface, j)
+			}
+			s.joined = nil
+			s.mu.Unlock()
+
 			return err
 		}
+
+		s.mu.Lock()
+		s.joined = append(s.joined, g)
+		s.mu.Unlock()
 	}
 
-	// Set up IPv6 packet connection, and on return, handle leaving multicast
-	// groups and closing connection
+	// Store the connection so Close can stop the server
+	s.mu.Lock()
+	s.conn = p
+	s.mu.Unlock()
+
+	// On return, handle leaving multicast groups and closing connection,
+	// unless Close already has, and wait for in-flight requests
 	defer func() {
-		for _, g := range s.MulticastGroups {
-			_ = p.LeaveGroup(s.Iface, g)
-		}
+		s.mu.Lock()
+		_ = s.closeLocked()
+		s.closing = false
+		s.mu.Unlock()
+
+		s.wg.Wait()
+	}()
 
-		_ = p.Close()
+	// Close the server when ctx is canceled.  Wait for the goroutine to
+	// exit before cleaning up, so it cannot close the connection of a later
+	// call to Serve.
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	defer func() {
+		close(done)
+		<-stopped
+	}()
+	go func() {
+		defer close(stopped)
+		select {
+		case <-ctx.Done():
+			_ = s.Close()
+		case <-done:
+		}
 	}()
 
-	// Loop and read requests until exit
-	buf := make([]byte, 1500)
+	// Loop and read requests until exit, reading each request into a
+	// pooled buffer which is reused once the request has been served
+	var tempDelay time.Duration
 	for {
-		n, cm, addr, err := p.ReadFrom(buf)
+		bufp := s.getBuffer()
+		n, cm, addr, err := p.ReadFrom(*bufp)
 		if err != nil {
+			s.putBuffer(bufp)
+
 			// Stop serve loop gracefully when closing
-			if err == errClosing {
-				return nil
+			if err == errClosing || s.isClosing() {
+				return ctx.Err()
+			}
+
+			// Back off and retry on temporary errors, as net/http does
+			if ne, ok := err.(net.Error); ok && ne.Temporary() {
+				if tempDelay == 0 {
+					tempDelay = 5 * time.Millisecond
+				} else {
+					tempDelay *= 2
+				}
+				if tempDelay > maxTempDelay {
+					tempDelay = maxTempDelay
+				}
+
+				s.logf("read error: %v; retrying in %v", err, tempDelay)
+				time.Sleep(tempDelay)
+				continue
 			}
 
-			// BUG(mdlayher): determine if error can be temporary
 			return err
 		}
+		tempDelay = 0
 
 		// Filter any traffic with a control message indicating an incorrect
 		// interface index
 		if cm != nil && cm.IfIndex != s.Iface.Index {
+			s.putBuffer(bufp)
 			continue
 		}
 
 		// Create conn struct with data specific to this connection
-		uc, err := s.newConn(p, addr.(*net.UDPAddr), n, buf)
+		uc, err := s.newConn(p, addr.(*net.UDPAddr), n, *bufp)
 		if err != nil {
+			s.putBuffer(bufp)
 			continue
 		}
+		if cm != nil {
+			uc.dst = cm.Dst
+		}
+
+		// A full buffer likely means the request was larger than the
+		// buffer, and was truncated
+		uc.truncated = n == len(*bufp)
+		if uc.truncated {
+			s.logf("%s: request may be truncated: filled %d byte read buffer", addr, n)
+		}
 
 		// Serve conn and continue looping for more connections
-		go uc.serve()
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer s.putBuffer(bufp)
+			uc.serve()
+		}()
 	}
 }
 
+// maxTempDelay is the maximum time a Server waits before retrying a read
+// after a temporary error.
+const maxTempDelay = 1 * time.Second
+
+// maxDUIDLLHardwareAddrLen and maxDUIDLLTHardwareAddrLen are the maximum
+// lengths of a hardware address in a DUID-LL and DUID-LLT.  A DUID may be no
+// more than 128 bytes long, not including its type, as described in
+// RFC 3315, Section 9.1.
+const (
+	maxDUIDLLHardwareAddrLen  = 128 - 2
+	maxDUIDLLTHardwareAddrLen = 128 - 2 - 4
+)
+
+// serverDUID generates a DUID for a server which listens on ifi.
+//
+// If ifi has a hardware address, a DUID-LL is generated.  The hardware type
+// is inferred from the length of the address, assuming the "Ethernet 10Mb"
+// hardware type unless the address is the length of an InfiniBand address.
+// If ifi has no hardware address, such as a tunnel interface, a DUID-UUID
+// is generated using a random UUID.
+func serverDUID(ifi *net.Interface) (dhcp6opts.DUID, error) {
+	switch l := len(ifi.HardwareAddr); {
+	case l == 0:
+		// Generate a version 4 UUID, as described in RFC 4122, Section 4.4.
+		var uuid [16]byte
+		if _, err := rand.Read(uuid[:]); err != nil {
+			return nil, err
+		}
+		uuid[6] = (uuid[6] & 0x0f) | 0x40
+		uuid[8] = (uuid[8] & 0x3f) | 0x80
+
+		return dhcp6opts.NewDUIDUUID(uuid), nil
+	case l > maxDUIDLLHardwareAddrLen:
+		return nil, fmt.Errorf("hardware address of interface %q is too long to generate a DUID: %d bytes",
+			ifi.Name, l)
+	default:
+		return dhcp6opts.NewDUIDLL(hardwareType(ifi.HardwareAddr), ifi.HardwareAddr), nil
+	}
+}
+
+// persistentServerDUID loads a server DUID from the file at path.  If the
+// file does not exist, a DUID-LLT is generated for a server which listens on
+// ifi and stored in the file.  If ifi's hardware address cannot be used in a
+// DUID-LLT, a DUID is generated using serverDUID instead.
+func persistentServerDUID(path string, ifi *net.Interface) (dhcp6opts.DUID, error) {
+	b, err := ioutil.ReadFile(path)
+	if err == nil {
+		// Reuse Server Identifier option parsing to decode the DUID.
+		o := make(dhcp6.Options)
+		o.AddRaw(dhcp6.OptionServerID, b)
+
+		duid, err := dhcp6opts.GetServerID(o)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse DUID from %q: %v", path, err)
+		}
+
+		return duid, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	var duid dhcp6opts.DUID
+	if l := len(ifi.HardwareAddr); l > 0 && l <= maxDUIDLLTHardwareAddrLen {
+		// DUID-LLT time has a resolution of one second, so truncate the
+		// current time to match the DUID which will be loaded later.
+		now := time.Now().Truncate(time.Second)
+		duid, err = dhcp6opts.NewDUIDLLT(hardwareType(ifi.HardwareAddr), now, ifi.HardwareAddr)
+	} else {
+		duid, err = serverDUID(ifi)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	b, err = duid.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ioutil.WriteFile(path, b, 0644); err != nil {
+		return nil, err
+	}
+
+	return duid, nil
+}
+
+// unicastAddr returns the first global unicast or unique local IPv6 address
+// of s.Iface, or nil if it has none.
+func (s *Server) unicastAddr() (net.IP, error) {
+	if s.Iface == nil {
+		return nil, nil
+	}
+
+	addrs := s.interfaceAddrs
+	if addrs == nil {
+		addrs = (*net.Interface).Addrs
+	}
+
+	as, err := addrs(s.Iface)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, a := range as {
+		var ip net.IP
+		switch a := a.(type) {
+		case *net.IPNet:
+			ip = a.IP
+		case *net.IPAddr:
+			ip = a.IP
+		}
+
+		if ip.To16() != nil && ip.To4() == nil && ip.IsGlobalUnicast() {
+			return ip, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// hardwareType infers the hardware type of a hardware address from its
+// length, assuming the "Ethernet 10Mb" hardware type unless the address is
+// the length of an InfiniBand address.
+func hardwareType(addr net.HardwareAddr) uint16 {
+	const (
+		ethernet10Mb uint16 = 1
+		infiniBand   uint16 = 32
+
+		infiniBandAddrLen = 20
+	)
+
+	if len(addr) == infiniBandAddrLen {
+		return infiniBand
+	}
+
+	return ethernet10Mb
+}
+
 // conn represents an in-flight DHCP connection, and contains information about
 // the connection and server.
 type conn struct {
@@ -213,21 +773,20 @@ type conn struct {
 	remoteAddr *net.UDPAddr
 	server     *Server
 	buf        []byte
+	dst        net.IP
+	truncated  bool
 }
 
 // newConn creates a new conn using information received in a single DHCP
-// connection.  newConn makes a copy of the input buffer for use in handling
-// a single connection.
-// BUG(mdlayher): consider using a sync.Pool with many buffers available to avoid
-// allocating a new one on each connection
+// connection.  newConn does not copy the input buffer, so it must not be
+// reused until the conn has been served.
 func (s *Server) newConn(p PacketConn, addr *net.UDPAddr, n int, buf []byte) (*conn, error) {
 	c := &conn{
 		conn:       p,
 		remoteAddr: addr,
 		server:     s,
-		buf:        make([]byte, n),
+		buf:        buf[:n],
 	}
-	copy(c.buf, buf[:n])
 
 	return c, nil
 }
@@ -238,6 +797,8 @@ type response struct {
 	conn       PacketConn
 	remoteAddr *net.UDPAddr
 	req        *Request
+	src        net.IP
+	unicast    net.IP
 
 	options dhcp6.Options
 }
@@ -252,7 +813,26 @@ func (r *response) Options() dhcp6.Options {
 // Send uses the input message typ, the transaction ID sent by a client,
 // and the options set by Options, to create and send a Packet to the
 // client's address.
+//
+// If mt is MessageTypeAdvertise and a Server Unicast option is present,
+// ErrUnicastInAdvertise is returned and no packet is sent.
 func (r *response) Send(mt dhcp6.MessageType) (int, error) {
+	if _, ok := r.options[dhcp6.OptionUnicast]; ok && mt == dhcp6.MessageTypeAdvertise {
+		return 0, ErrUnicastInAdvertise
+	}
+
+	// Add the server's unicast address, if permitted and not already set
+	// by the handler.  A message which carries an Authentication option
+	// was signed by the handler, and adding an option would invalidate
+	// its signature, so it is sent unmodified.
+	if r.unicast != nil && mt != dhcp6.MessageTypeAdvertise {
+		_, unicast := r.options[dhcp6.OptionUnicast]
+		_, auth := r.options[dhcp6.OptionAuth]
+		if !unicast && !auth {
+			_ = r.options.Add(dhcp6.OptionUnicast, dhcp6opts.IP(r.unicast))
+		}
+	}
+
 	p := &dhcp6.Packet{
 		MessageType:   mt,
 		TransactionID: r.req.TransactionID,
@@ -264,7 +844,52 @@ func (r *response) Send(mt dhcp6.MessageType) (int, error) {
 		return 0, err
 	}
 
-	return r.conn.WriteTo(b, nil, r.remoteAddr)
+	// If the request was relayed, the response must be relayed back
+	// through the same relay agents.
+	b, err = relayReply(r.req.Relays, b)
+	if err != nil {
+		return 0, err
+	}
+
+	// Use the configured source address for the reply, if set.
+	var cm *ipv6.ControlMessage
+	if r.src != nil {
+		cm = &ipv6.ControlMessage{
+			Src: r.src,
+		}
+	}
+
+	return r.conn.WriteTo(b, cm, r.remoteAddr)
+}
+
+// relayReply encapsulates a response message b in a Relay-reply message for
+// each Relay-forward message in relays, as described in RFC 3315,
+// Section 20.3.  If relays is empty, b is returned unmodified.
+func relayReply(relays []*dhcp6opts.RelayMessage, b []byte) ([]byte, error) {
+	for i := len(relays) - 1; i >= 0; i-- {
+		rf := relays[i]
+		rr := &dhcp6opts.RelayMessage{
+			MessageType: dhcp6.MessageTypeRelayRepl,
+			HopCount:    rf.HopCount,
+			LinkAddress: rf.LinkAddress,
+			PeerAddress: rf.PeerAddress,
+			Options:     make(dhcp6.Options),
+		}
+
+		// Interface-ID must be copied from Relay-forward, if present.
+		if id, err := rf.Options.GetOne(dhcp6.OptionInterfaceID); err == nil {
+			rr.Options.AddRaw(dhcp6.OptionInterfaceID, id)
+		}
+		rr.Options.AddRaw(dhcp6.OptionRelayMsg, b)
+
+		var err error
+		b, err = rr.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return b, nil
 }
 
 // serve handles serving an individual DHCP connection, and is invoked in a
@@ -283,22 +908,42 @@ func (c *conn) serve() {
 		c.server.logf("%s: error parsing request: %s", c.remoteAddr.String(), err.Error())
 		return
 	}
+	r.dst = c.dst
+	r.Truncated = c.truncated
+
+	// Drop requests with an all-zeros transaction ID, if configured.
+	if c.server.RejectZeroTransactionID && r.TransactionID == [3]byte{} {
+		return
+	}
 
-	// Filter out unknown/invalid message types, using the lowest and highest
-	// numbered types
-	if r.MessageType < dhcp6.MessageTypeSolicit || r.MessageType > dhcp6.MessageTypeDHCPv4Response {
+	// Filter out unknown/invalid message types
+	if !knownMessageType(r.MessageType) {
 		c.server.logf("%s: unrecognized message type: %d", c.remoteAddr.String(), r.MessageType)
 		return
 	}
 
+	// Reply from the address the client contacted, if configured and the
+	// request was not sent to a multicast group.
+	src := c.server.ReplySourceAddr
+	if src == nil && c.server.ReplyFromRequestAddr && c.dst != nil && !c.dst.IsMulticast() {
+		src = c.dst
+	}
+
 	// Set up response to send responses back to the original requester
 	w := &response{
 		remoteAddr: c.remoteAddr,
 		conn:       c.conn,
 		req:        r,
+		src:        src,
 		options:    make(dhcp6.Options),
 	}
 
+	// Clients cannot send messages directly to a server which they
+	// reach through a relay agent.
+	if len(r.Relays) == 0 {
+		w.unicast = c.server.unicast
+	}
+
 	// Add server ID to response
 	if sID := c.server.ServerID; sID != nil {
 		_ = w.options.Add(dhcp6.OptionServerID, sID)
@@ -317,3 +962,15 @@ func (c *conn) serve() {
 
 	handler.ServeDHCP(w, r)
 }
+
+// knownMessageType reports whether mt is a message type which may be served:
+// one of the contiguous RFC 3315 through RFC 7341 types, or an RFC 9686
+// address registration type.
+func knownMessageType(mt dhcp6.MessageType) bool {
+	switch mt {
+	case dhcp6.MessageTypeAddrRegInform, dhcp6.MessageTypeAddrRegReply:
+		return true
+	}
+
+	return mt >= dhcp6.MessageTypeSolicit && mt <= dhcp6.MessageTypeDHCPv4Response
+}
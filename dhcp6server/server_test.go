@@ -2,10 +2,16 @@ package dhcp6server
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"io/ioutil"
 	"log"
 	"net"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -70,11 +76,460 @@ func TestServeIPv6ControlParameters(t *testing.T) {
 		t.Fatalf("FlagInterface not found or not set to true:\n- found: %v\n-  bool: %v", ok, b)
 	}
 
+	if b, ok := ip6.flags[ipv6.FlagDst]; !ok || !b {
+		t.Fatalf("FlagDst not found or not set to true:\n- found: %v\n-  bool: %v", ok, b)
+	}
+
 	if !ip6.closed {
 		t.Fatal("IPv6 connection not closed after Serve")
 	}
 }
 
+// TestServeJoinGroupError verifies that Serve leaves any multicast groups it
+// already joined when joining a later group fails.
+func TestServeJoinGroupError(t *testing.T) {
+	s := &Server{
+		Iface: &net.Interface{
+			Name:  "foo0",
+			Index: 0,
+		},
+		MulticastGroups: []*net.IPAddr{
+			AllRelayAgentsAndServersAddr,
+			AllServersAddr,
+		},
+	}
+
+	errJoin := errors.New("join failed")
+	ip6 := &recordIPv6PacketConn{
+		flags: make(map[ipv6.ControlFlags]bool),
+	}
+	c := &joinErrorPacketConn{
+		PacketConn: &testPacketConn{
+			recordIPv6PacketConn: ip6,
+		},
+		n:   1,
+		err: errJoin,
+	}
+
+	if want, got := errJoin, s.Serve(c); want != got {
+		t.Fatalf("unexpected Serve error: %v != %v", want, got)
+	}
+
+	if want, got := []net.Addr{AllRelayAgentsAndServersAddr}, ip6.left; !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected left groups:\n- want: %v\n-  got: %v", want, got)
+	}
+
+	if l := len(s.JoinedGroups()); l > 0 {
+		t.Fatalf("expected no joined groups after Serve, but got %d", l)
+	}
+}
+
+// TestServeJoinedGroups verifies that Server.JoinedGroups reports the
+// multicast groups joined while a Server is serving, and that no groups are
+// reported once Serve returns.
+func TestServeJoinedGroups(t *testing.T) {
+	s := &Server{
+		Iface: &net.Interface{
+			Name:  "foo0",
+			Index: 0,
+		},
+		MulticastGroups: []*net.IPAddr{
+			AllRelayAgentsAndServersAddr,
+			AllServersAddr,
+		},
+	}
+
+	// Capture joined groups on first read, then stop the server
+	c := &joinedGroupsPacketConn{
+		PacketConn: &testPacketConn{
+			recordIPv6PacketConn: &recordIPv6PacketConn{
+				flags: make(map[ipv6.ControlFlags]bool),
+			},
+		},
+		s: s,
+	}
+
+	if err := s.Serve(c); err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := s.MulticastGroups, c.joined; !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected joined groups:\n- want: %v\n-  got: %v", want, got)
+	}
+
+	if l := len(s.JoinedGroups()); l > 0 {
+		t.Fatalf("expected no joined groups after Serve, but got %d", l)
+	}
+}
+
+// TestServeAlreadyRunning verifies that Serve and ListenAndServe return
+// ErrServerRunning while a Server is running, and that the Server may be
+// started again once Serve returns.
+func TestServeAlreadyRunning(t *testing.T) {
+	s := &Server{
+		Iface: &net.Interface{
+			Name:  "foo0",
+			Index: 0,
+		},
+	}
+
+	newConn := func(fn func()) PacketConn {
+		return &readFuncPacketConn{
+			PacketConn: &testPacketConn{
+				recordIPv6PacketConn: &recordIPv6PacketConn{
+					flags: make(map[ipv6.ControlFlags]bool),
+				},
+			},
+			fn: fn,
+		}
+	}
+
+	var serveErr, listenErr error
+	c := newConn(func() {
+		serveErr = s.Serve(newConn(func() {}))
+		listenErr = s.ListenAndServe()
+	})
+
+	if err := s.Serve(c); err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := ErrServerRunning, serveErr; want != got {
+		t.Fatalf("unexpected Serve error: %v != %v", want, got)
+	}
+	if want, got := ErrServerRunning, listenErr; want != got {
+		t.Fatalf("unexpected ListenAndServe error: %v != %v", want, got)
+	}
+
+	// Server should be able to serve again after stopping
+	if err := s.Serve(newConn(func() {})); err != nil {
+		t.Fatalf("unexpected error restarting server: %v", err)
+	}
+}
+
+// TestServerClose verifies that Server.Close stops a running server, leaves
+// its multicast groups, and may be called concurrently.
+func TestServerClose(t *testing.T) {
+	s := &Server{
+		Iface: &net.Interface{
+			Name:  "foo0",
+			Index: 0,
+		},
+		MulticastGroups: []*net.IPAddr{
+			AllRelayAgentsAndServersAddr,
+			AllServersAddr,
+		},
+	}
+
+	// Closing a server which is not serving does nothing
+	if err := s.Close(); err != nil {
+		t.Fatalf("unexpected error closing idle server: %v", err)
+	}
+
+	c := &blockingPacketConn{
+		recordIPv6PacketConn: &recordIPv6PacketConn{
+			flags: make(map[ipv6.ControlFlags]bool),
+		},
+		readyC: make(chan struct{}),
+		closeC: make(chan struct{}),
+	}
+
+	errC := make(chan error, 1)
+	go func() {
+		errC <- s.Serve(c)
+	}()
+	<-c.readyC
+
+	// Close concurrently to verify it is safe to do so
+	closeErrC := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			closeErrC <- s.Close()
+		}()
+	}
+	for i := 0; i < 2; i++ {
+		if err := <-closeErrC; err != nil {
+			t.Fatalf("unexpected error closing server: %v", err)
+		}
+	}
+
+	if err := <-errC; err != nil {
+		t.Fatalf("unexpected Serve error after Close: %v", err)
+	}
+
+	if !c.closed {
+		t.Fatal("connection was not closed")
+	}
+
+	var want []net.Addr
+	for _, g := range s.MulticastGroups {
+		want = append(want, g)
+	}
+	if got := c.left; !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected left groups:\n- want: %v\n-  got: %v", want, got)
+	}
+
+	// Server should be able to serve again after closing
+	if err := s.Serve(&readFuncPacketConn{
+		PacketConn: &testPacketConn{
+			recordIPv6PacketConn: &recordIPv6PacketConn{
+				flags: make(map[ipv6.ControlFlags]bool),
+			},
+		},
+		fn: func() {},
+	}); err != nil {
+		t.Fatalf("unexpected error restarting server: %v", err)
+	}
+}
+
+// TestServeContextCanceled verifies that ServeContext stops serving and
+// returns the context's error once its context is canceled.
+func TestServeContextCanceled(t *testing.T) {
+	newConn := func() *blockingPacketConn {
+		return &blockingPacketConn{
+			recordIPv6PacketConn: &recordIPv6PacketConn{
+				flags: make(map[ipv6.ControlFlags]bool),
+			},
+			readyC: make(chan struct{}),
+			closeC: make(chan struct{}),
+		}
+	}
+
+	s := &Server{
+		Iface: &net.Interface{
+			Name:  "foo0",
+			Index: 0,
+		},
+	}
+
+	// Cancel while the server is reading
+	ctx, cancel := context.WithCancel(context.Background())
+	c := newConn()
+
+	errC := make(chan error, 1)
+	go func() {
+		errC <- s.ServeContext(ctx, c)
+	}()
+	<-c.readyC
+	cancel()
+
+	if want, got := context.Canceled, <-errC; want != got {
+		t.Fatalf("unexpected ServeContext error: %v != %v", want, got)
+	}
+	if !c.closed {
+		t.Fatal("connection was not closed")
+	}
+
+	// Context canceled before serving begins
+	c = newConn()
+	if want, got := context.Canceled, s.ServeContext(ctx, c); want != got {
+		t.Fatalf("unexpected ServeContext error with canceled context: %v != %v", want, got)
+	}
+}
+
+// TestServeReadErrors verifies that Serve retries reads after temporary
+// errors, but returns on any other error.
+func TestServeReadErrors(t *testing.T) {
+	errFatal := errors.New("fatal error")
+
+	var tests = []struct {
+		desc  string
+		errs  []error
+		reads int
+		err   error
+	}{
+		{
+			desc:  "temporary errors",
+			errs:  []error{tempError{}, tempError{}, tempError{}},
+			reads: 4,
+		},
+		{
+			desc:  "fatal error after temporary error",
+			errs:  []error{tempError{}, errFatal, tempError{}},
+			reads: 2,
+			err:   errFatal,
+		},
+	}
+
+	for i, tt := range tests {
+		s := &Server{
+			Iface: &net.Interface{
+				Name:  "foo0",
+				Index: 0,
+			},
+		}
+
+		c := &errorsPacketConn{
+			PacketConn: &testPacketConn{
+				recordIPv6PacketConn: &recordIPv6PacketConn{
+					flags: make(map[ipv6.ControlFlags]bool),
+				},
+			},
+			errs: tt.errs,
+		}
+
+		if want, got := tt.err, s.Serve(c); want != got {
+			t.Fatalf("[%02d] test %q, unexpected Serve error: %v != %v",
+				i, tt.desc, want, got)
+		}
+
+		if want, got := tt.reads, c.reads; want != got {
+			t.Fatalf("[%02d] test %q, unexpected number of reads: %v != %v",
+				i, tt.desc, want, got)
+		}
+	}
+}
+
+// TestServeReadBufferSize verifies that Server.ReadBufferSize sizes the
+// buffer requests are read into, and that a request which fills the buffer
+// is reported as possibly truncated.
+func TestServeReadBufferSize(t *testing.T) {
+	p := &dhcp6.Packet{
+		MessageType:   dhcp6.MessageTypeSolicit,
+		TransactionID: [3]byte{1, 2, 3},
+		Options:       make(dhcp6.Options),
+	}
+	p.Options.AddRaw(dhcp6.OptionUserClass, make([]byte, 1800))
+
+	pb, err := p.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = []struct {
+		desc      string
+		size      int
+		ifIndex   int
+		handled   bool
+		truncated bool
+	}{
+		{
+			desc:      "default size, request truncated",
+			truncated: true,
+		},
+		{
+			desc:    "default size, request from another interface",
+			ifIndex: 1,
+		},
+		{
+			desc:    "jumbo size",
+			size:    9000,
+			handled: true,
+		},
+	}
+
+	for i, tt := range tests {
+		var length int64
+		logBuf := bytes.NewBuffer(nil)
+
+		s := &Server{
+			Iface:          &net.Interface{Name: "foo0", Index: 0},
+			ServerID:       dhcp6opts.NewDUIDLL(1, net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad}),
+			ReadBufferSize: tt.size,
+			ErrorLog:       log.New(logBuf, "", 0),
+			Handler: HandlerFunc(func(w ResponseSender, r *Request) {
+				length = r.Length
+			}),
+		}
+
+		c := &repeatPacketConn{
+			PacketConn: &testPacketConn{
+				recordIPv6PacketConn: &recordIPv6PacketConn{
+					flags: make(map[ipv6.ControlFlags]bool),
+				},
+			},
+			b:  pb,
+			cm: &ipv6.ControlMessage{IfIndex: tt.ifIndex},
+			n:  1,
+		}
+
+		if err := s.Serve(c); err != nil {
+			t.Fatal(err)
+		}
+
+		// A truncated request cannot be parsed, so it is never handled
+		if want, got := tt.handled, length == int64(len(pb)); want != got {
+			t.Fatalf("[%02d] test %q, unexpected handled value: %v != %v",
+				i, tt.desc, want, got)
+		}
+
+		logged := strings.Contains(logBuf.String(), "may be truncated")
+		if want, got := tt.truncated, logged; want != got {
+			t.Fatalf("[%02d] test %q, unexpected truncation warning: %v != %v",
+				i, tt.desc, want, got)
+		}
+	}
+}
+
+// TestServeRequestTruncated verifies that Serve marks a request which fills
+// the entire read buffer as truncated.
+func TestServeRequestTruncated(t *testing.T) {
+	p := &dhcp6.Packet{
+		MessageType:   dhcp6.MessageTypeSolicit,
+		TransactionID: [3]byte{1, 2, 3},
+		Options:       make(dhcp6.Options),
+	}
+	p.Options.AddRaw(dhcp6.OptionUserClass, make([]byte, 100))
+
+	pb, err := p.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = []struct {
+		desc      string
+		size      int
+		truncated bool
+	}{
+		{
+			desc:      "request exactly fills buffer",
+			size:      len(pb),
+			truncated: true,
+		},
+		{
+			desc: "request one byte smaller than buffer",
+			size: len(pb) + 1,
+		},
+	}
+
+	for i, tt := range tests {
+		var handled, truncated bool
+
+		s := &Server{
+			Iface:          &net.Interface{Name: "foo0", Index: 0},
+			ServerID:       dhcp6opts.NewDUIDLL(1, net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad}),
+			ReadBufferSize: tt.size,
+			ErrorLog:       log.New(ioutil.Discard, "", 0),
+			Handler: HandlerFunc(func(w ResponseSender, r *Request) {
+				handled = true
+				truncated = r.Truncated
+			}),
+		}
+
+		c := &repeatPacketConn{
+			PacketConn: &testPacketConn{
+				recordIPv6PacketConn: &recordIPv6PacketConn{
+					flags: make(map[ipv6.ControlFlags]bool),
+				},
+			},
+			b: pb,
+			n: 1,
+		}
+
+		if err := s.Serve(c); err != nil {
+			t.Fatal(err)
+		}
+
+		if !handled {
+			t.Fatalf("[%02d] test %q, request was not handled", i, tt.desc)
+		}
+		if want, got := tt.truncated, truncated; want != got {
+			t.Fatalf("[%02d] test %q, unexpected Request.Truncated: %v != %v",
+				i, tt.desc, want, got)
+		}
+	}
+}
+
 // TestServeWithSetServerID verifies that Serve uses the server ID provided
 // instead of generating its own, when a server ID is set.
 func TestServeWithSetServerID(t *testing.T) {
@@ -240,8 +695,14 @@ func TestServeIgnoreInvalidPacket(t *testing.T) {
 // TestServeIgnoreBadMessageType verifies that Serve will ignore request
 // packets with invalid message types.
 func TestServeIgnoreBadMessageType(t *testing.T) {
-	// Message types not known
+	// Message types not known, including the unassigned types between
+	// DHCPv4-response and Addr-Reg-Inform
 	badMT := []byte{0, 22}
+	for mt := byte(23); mt <= 35; mt++ {
+		badMT = append(badMT, mt)
+	}
+	badMT = append(badMT, 38)
+
 	for _, mt := range badMT {
 		r := &testMessage{}
 		r.b.Write([]byte{mt, 0, 0, 0})
@@ -308,9 +769,9 @@ func TestServeOK(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	// For now, outgoing control message is always nil
+	// Without a reply source address, outgoing control message is nil
 	if w.cm != nil {
-		t.Fatal("control message is never set on outgoing reply")
+		t.Fatal("control message should not be set on outgoing reply")
 	}
 	if want, got := r.addr, w.addr; want != got {
 		t.Fatalf("unexpected client address: %v != %v", want, got)
@@ -360,64 +821,586 @@ func TestServeOK(t *testing.T) {
 	}
 }
 
-// testServe performs a single transaction using the input message, server
-// configuration, whether or not a reply is expected, and a closure which
-// acts as a HandlerFunc.
-func testServe(r *testMessage, s *Server, expectReply bool, fn func(w ResponseSender, r *Request)) (*testMessage, *recordIPv6PacketConn, error) {
-	// If caller doesn't specify a testMessage or client address
-	// for it, configure it for them
-	if r == nil {
-		r = &testMessage{}
+// TestServeRelayed verifies that Serve handles a request relayed by a DHCP
+// relay agent, and sends the response back through the relay agent in a
+// Relay-reply message.
+func TestServeRelayed(t *testing.T) {
+	txID := [3]byte{0, 1, 2}
+	p := &dhcp6.Packet{
+		MessageType:   dhcp6.MessageTypeSolicit,
+		TransactionID: txID,
+		Options:       make(dhcp6.Options),
 	}
-	if r.addr == nil {
-		r.addr = &net.UDPAddr{
-			IP: net.ParseIP("::1"),
-		}
+
+	var rmo dhcp6opts.RelayMessageOption
+	if err := rmo.SetClientServerMessage(p); err != nil {
+		t.Fatal(err)
 	}
 
-	// If caller doesn't specify Server value, configure it for
-	// them using input function as HandlerFunc.
-	if s == nil {
-		s = &Server{}
+	interfaceID := dhcp6opts.InterfaceID("eth0")
+	rf := &dhcp6opts.RelayMessage{
+		MessageType: dhcp6.MessageTypeRelayForw,
+		LinkAddress: net.ParseIP("2001:db8::1"),
+		PeerAddress: net.ParseIP("fe80::1"),
+		Options:     make(dhcp6.Options),
 	}
-	if s.Iface == nil {
-		s.Iface = &net.Interface{
-			Name:  "foo0",
-			Index: 0,
-		}
+	rf.Options.Add(dhcp6.OptionInterfaceID, &interfaceID)
+	rf.Options.Add(dhcp6.OptionRelayMsg, &rmo)
+
+	rfb, err := rf.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
 	}
-	s.Handler = HandlerFunc(fn)
 
-	// Implements PacketConn to capture request/response
-	tc := &testPacketConn{
-		r: r,
-		w: &testMessage{},
+	r := &testMessage{}
+	r.b.Write(rfb)
 
-		// Record IPv6 control parameters
-		recordIPv6PacketConn: &recordIPv6PacketConn{
-			joined: make([]net.Addr, 0),
-			left:   make([]net.Addr, 0),
-			flags:  make(map[ipv6.ControlFlags]bool),
-		},
+	mt := dhcp6.MessageTypeAdvertise
+	w, _, err := testServe(r, nil, true, func(w ResponseSender, r *Request) {
+		w.Send(mt)
+	})
+	if err != nil {
+		t.Fatal(err)
 	}
 
-	// Perform a single read and possibly write before returning
-	// an error on second read to close server
-	c := &oneReadPacketConn{
-		PacketConn: tc,
-
-		readDoneC:  make(chan struct{}),
-		writeDoneC: make(chan struct{}),
+	rr := new(dhcp6opts.RelayMessage)
+	if err := rr.UnmarshalBinary(w.b.Bytes()); err != nil {
+		t.Fatal(err)
 	}
 
-	// If no reply is expected, this channel will never be closed,
-	// and should be closed immediately
-	if !expectReply {
-		close(c.writeDoneC)
+	if want, got := dhcp6.MessageTypeRelayRepl, rr.MessageType; want != got {
+		t.Fatalf("unexpected relay message type: %v != %v", want, got)
+	}
+	if want, got := rf.LinkAddress, rr.LinkAddress; !want.Equal(got) {
+		t.Fatalf("unexpected link-address: %v != %v", want, got)
+	}
+	if want, got := rf.PeerAddress, rr.PeerAddress; !want.Equal(got) {
+		t.Fatalf("unexpected peer-address: %v != %v", want, got)
 	}
 
-	// Handle request
-	err := s.Serve(c)
+	id, err := dhcp6opts.GetInterfaceID(rr.Options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := interfaceID, id; !bytes.Equal(want, got) {
+		t.Fatalf("unexpected interface-ID:\n- want: %v\n-  got: %v", want, got)
+	}
+
+	msg, err := dhcp6opts.GetRelayMessageOption(rr.Options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wp, err := msg.ClientServerMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := mt, wp.MessageType; want != got {
+		t.Fatalf("unexpected message type: %v != %v", want, got)
+	}
+	if want, got := txID, wp.TransactionID; want != got {
+		t.Fatalf("unexpected transaction ID: %v != %v", want, got)
+	}
+}
+
+// TestServeReplySourceAddr verifies that Serve sets the source address of the
+// outgoing control message when Server.ReplySourceAddr is set.
+func TestServeReplySourceAddr(t *testing.T) {
+	p := &dhcp6.Packet{
+		MessageType:   dhcp6.MessageTypeSolicit,
+		TransactionID: [3]byte{0, 1, 2},
+	}
+
+	pb, err := p.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := &testMessage{}
+	r.b.Write(pb)
+
+	src := net.ParseIP("fe80::1")
+	s := &Server{
+		ReplySourceAddr: src,
+	}
+
+	w, _, err := testServe(r, s, true, func(w ResponseSender, r *Request) {
+		w.Send(dhcp6.MessageTypeAdvertise)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if w.cm == nil {
+		t.Fatal("control message should be set on outgoing reply")
+	}
+	if want, got := src, w.cm.Src; !want.Equal(got) {
+		t.Fatalf("unexpected reply source address: %v != %v", want, got)
+	}
+}
+
+// TestServeAllowUnicast verifies that Serve adds a Server Unicast option
+// using an address derived from the server's interface when
+// Server.AllowUnicast is set.
+func TestServeAllowUnicast(t *testing.T) {
+	linkLocal := &net.IPNet{IP: net.ParseIP("fe80::1"), Mask: net.CIDRMask(64, 128)}
+	ipv4 := &net.IPNet{IP: net.IPv4(192, 0, 2, 1), Mask: net.CIDRMask(24, 32)}
+	global := &net.IPNet{IP: net.ParseIP("2001:db8::1"), Mask: net.CIDRMask(64, 128)}
+	ula := &net.IPNet{IP: net.ParseIP("fd00::1"), Mask: net.CIDRMask(64, 128)}
+
+	var tests = []struct {
+		desc    string
+		allow   bool
+		addrs   []net.Addr
+		mt      dhcp6.MessageType
+		auth    bool
+		unicast net.IP
+	}{
+		{
+			desc:  "unicast not allowed",
+			addrs: []net.Addr{global},
+			mt:    dhcp6.MessageTypeReply,
+		},
+		{
+			desc:    "global unicast address",
+			allow:   true,
+			addrs:   []net.Addr{linkLocal, ipv4, global},
+			mt:      dhcp6.MessageTypeReply,
+			unicast: global.IP,
+		},
+		{
+			desc:    "unique local address",
+			allow:   true,
+			addrs:   []net.Addr{linkLocal, ula},
+			mt:      dhcp6.MessageTypeReply,
+			unicast: ula.IP,
+		},
+		{
+			desc:  "no usable address",
+			allow: true,
+			addrs: []net.Addr{linkLocal, ipv4},
+			mt:    dhcp6.MessageTypeReply,
+		},
+		{
+			desc:  "advertise",
+			allow: true,
+			addrs: []net.Addr{global},
+			mt:    dhcp6.MessageTypeAdvertise,
+		},
+		{
+			desc:  "authenticated reply",
+			allow: true,
+			addrs: []net.Addr{global},
+			mt:    dhcp6.MessageTypeReply,
+			auth:  true,
+		},
+	}
+
+	for i, tt := range tests {
+		p := &dhcp6.Packet{
+			MessageType:   dhcp6.MessageTypeRequest,
+			TransactionID: [3]byte{0, 1, 2},
+		}
+
+		pb, err := p.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		r := &testMessage{}
+		r.b.Write(pb)
+
+		s := &Server{
+			AllowUnicast: tt.allow,
+			ErrorLog:     log.New(ioutil.Discard, "", 0),
+			interfaceAddrs: func(_ *net.Interface) ([]net.Addr, error) {
+				return tt.addrs, nil
+			},
+		}
+
+		var sendErr error
+		w, _, err := testServe(r, s, true, func(w ResponseSender, r *Request) {
+			if tt.auth {
+				w.Options().AddRaw(dhcp6.OptionAuth, make([]byte, 11))
+			}
+			_, sendErr = w.Send(tt.mt)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sendErr != nil {
+			t.Fatalf("[%02d] test %q, unexpected error sending response: %v",
+				i, tt.desc, sendErr)
+		}
+
+		wp := new(dhcp6.Packet)
+		if err := wp.UnmarshalBinary(w.b.Bytes()); err != nil {
+			t.Fatal(err)
+		}
+
+		ip, err := dhcp6opts.GetUnicast(wp.Options)
+		if tt.unicast == nil {
+			if err != dhcp6.ErrOptionNotPresent {
+				t.Fatalf("[%02d] test %q, unexpected Server Unicast option: %v",
+					i, tt.desc, ip)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if want, got := tt.unicast, net.IP(ip); !want.Equal(got) {
+			t.Fatalf("[%02d] test %q, unexpected unicast address: %v != %v",
+				i, tt.desc, want, got)
+		}
+	}
+}
+
+// TestServeReplyFromRequestAddr verifies that Serve sends replies from the
+// unicast address a request was sent to when Server.ReplyFromRequestAddr is
+// set.
+func TestServeReplyFromRequestAddr(t *testing.T) {
+	unicast := net.ParseIP("2001:db8::1")
+	configured := net.ParseIP("fe80::1")
+
+	var tests = []struct {
+		desc  string
+		s     *Server
+		dst   net.IP
+		src   net.IP
+		setCM bool
+	}{
+		{
+			desc: "disabled",
+			s:    &Server{},
+			dst:  unicast,
+		},
+		{
+			desc:  "unicast destination",
+			s:     &Server{ReplyFromRequestAddr: true},
+			dst:   unicast,
+			src:   unicast,
+			setCM: true,
+		},
+		{
+			desc: "multicast destination",
+			s:    &Server{ReplyFromRequestAddr: true},
+			dst:  AllServersAddr.IP,
+		},
+		{
+			desc: "ReplySourceAddr takes precedence",
+			s: &Server{
+				ReplySourceAddr:      configured,
+				ReplyFromRequestAddr: true,
+			},
+			dst:   unicast,
+			src:   configured,
+			setCM: true,
+		},
+	}
+
+	for i, tt := range tests {
+		p := &dhcp6.Packet{
+			MessageType:   dhcp6.MessageTypeRequest,
+			TransactionID: [3]byte{0, 1, 2},
+		}
+
+		pb, err := p.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		r := &testMessage{
+			cm: &ipv6.ControlMessage{
+				Dst: tt.dst,
+			},
+		}
+		r.b.Write(pb)
+
+		w, _, err := testServe(r, tt.s, true, func(w ResponseSender, r *Request) {
+			w.Send(dhcp6.MessageTypeReply)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !tt.setCM {
+			if w.cm != nil {
+				t.Fatalf("[%02d] test %q, unexpected control message: %v",
+					i, tt.desc, w.cm)
+			}
+			continue
+		}
+
+		if w.cm == nil {
+			t.Fatalf("[%02d] test %q, control message should be set on outgoing reply",
+				i, tt.desc)
+		}
+		if want, got := tt.src, w.cm.Src; !want.Equal(got) {
+			t.Fatalf("[%02d] test %q, unexpected reply source address: %v != %v",
+				i, tt.desc, want, got)
+		}
+	}
+}
+
+// TestServeMulticastGroup verifies that Serve surfaces the multicast group
+// a request was sent to using Request.MulticastGroup.
+func TestServeMulticastGroup(t *testing.T) {
+	p := &dhcp6.Packet{
+		MessageType:   dhcp6.MessageTypeSolicit,
+		TransactionID: [3]byte{0, 1, 2},
+	}
+
+	pb, err := p.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := &testMessage{
+		cm: &ipv6.ControlMessage{
+			Dst: AllServersAddr.IP,
+		},
+	}
+	r.b.Write(pb)
+
+	var group net.IP
+	var ok bool
+	_, _, err = testServe(r, &Server{}, true, func(w ResponseSender, r *Request) {
+		group, ok = r.MulticastGroup()
+		w.Send(dhcp6.MessageTypeAdvertise)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !ok {
+		t.Fatal("expected request to be sent to a multicast group")
+	}
+	if want, got := AllServersAddr.IP, group; !want.Equal(got) {
+		t.Fatalf("unexpected multicast group: %v != %v", want, got)
+	}
+}
+
+// TestServeAddrRegInform verifies that a Server passes an ADDR-REG-INFORM
+// message to its Handler, and that the Handler can acknowledge the
+// registered address with an ADDR-REG-REPLY message, as described in
+// RFC 9686, Section 4.
+func TestServeAddrRegInform(t *testing.T) {
+	addr, err := dhcp6opts.NewIAAddr(net.ParseIP("2001:db8::1"), 1*time.Hour, 2*time.Hour, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := &dhcp6.Packet{
+		MessageType:   dhcp6.MessageTypeAddrRegInform,
+		TransactionID: [3]byte{0, 1, 2},
+		Options:       make(dhcp6.Options),
+	}
+	if err := p.Options.Add(dhcp6.OptionIAAddr, addr); err != nil {
+		t.Fatal(err)
+	}
+
+	pb, err := p.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := &testMessage{}
+	r.b.Write(pb)
+
+	w, _, err := testServe(r, &Server{}, true, func(w ResponseSender, r *Request) {
+		if r.MessageType != dhcp6.MessageTypeAddrRegInform {
+			return
+		}
+
+		// Echo the registered address to acknowledge the registration.
+		iaaddrs, err := dhcp6opts.GetIAAddr(r.Options)
+		if err != nil || len(iaaddrs) != 1 {
+			return
+		}
+		_ = w.Options().Add(dhcp6.OptionIAAddr, iaaddrs[0])
+
+		w.Send(dhcp6.MessageTypeAddrRegReply)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reply := new(dhcp6.Packet)
+	if err := reply.UnmarshalBinary(w.b.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := dhcp6.MessageTypeAddrRegReply, reply.MessageType; want != got {
+		t.Fatalf("unexpected message type: %v != %v", want, got)
+	}
+
+	iaaddrs, err := dhcp6opts.GetIAAddr(reply.Options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := []*dhcp6opts.IAAddr{addr}, iaaddrs; !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected registered addresses:\n- want: %v\n-  got: %v", want, got)
+	}
+}
+
+// TestServeRejectZeroTransactionID verifies that a request with an all-zeros
+// transaction ID is dropped only when Server.RejectZeroTransactionID is set.
+func TestServeRejectZeroTransactionID(t *testing.T) {
+	p := &dhcp6.Packet{
+		MessageType: dhcp6.MessageTypeSolicit,
+	}
+
+	pb, err := p.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, reject := range []bool{false, true} {
+		var handled bool
+		s := &Server{
+			RejectZeroTransactionID: reject,
+			Handler: HandlerFunc(func(w ResponseSender, r *Request) {
+				handled = true
+			}),
+		}
+
+		tc := &testPacketConn{
+			w: &testMessage{},
+		}
+
+		// Serve the connection synchronously, so no reply can be missed.
+		c, err := s.newConn(tc, &net.UDPAddr{IP: net.ParseIP("::1")}, len(pb), pb)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c.serve()
+
+		if want, got := !reject, handled; want != got {
+			t.Fatalf("unexpected handled value with RejectZeroTransactionID %v: %v != %v",
+				reject, want, got)
+		}
+	}
+}
+
+// TestServeUnicastOption verifies that a Server Unicast option may be sent in
+// a Reply, but not in an Advertise.
+func TestServeUnicastOption(t *testing.T) {
+	var tests = []struct {
+		desc string
+		mt   dhcp6.MessageType
+		err  error
+	}{
+		{
+			desc: "Unicast in Reply",
+			mt:   dhcp6.MessageTypeReply,
+		},
+		{
+			desc: "Unicast in Advertise",
+			mt:   dhcp6.MessageTypeAdvertise,
+			err:  ErrUnicastInAdvertise,
+		},
+	}
+
+	for i, tt := range tests {
+		p := &dhcp6.Packet{
+			MessageType:   dhcp6.MessageTypeRequest,
+			TransactionID: [3]byte{0, 1, 2},
+		}
+
+		pb, err := p.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		r := &testMessage{}
+		r.b.Write(pb)
+
+		errC := make(chan error, 1)
+		w, _, err := testServe(r, nil, tt.err == nil, func(w ResponseSender, r *Request) {
+			w.Options().Add(dhcp6.OptionUnicast, dhcp6opts.IP(net.ParseIP("2001:db8::1")))
+			_, err := w.Send(tt.mt)
+			errC <- err
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		sendErr := <-errC
+
+		if want, got := tt.err, sendErr; want != got {
+			t.Fatalf("[%02d] test %q, unexpected error: %v != %v",
+				i, tt.desc, want, got)
+		}
+
+		if tt.err != nil {
+			if l := w.b.Len(); l > 0 {
+				t.Fatalf("[%02d] test %q, reply should be empty, but got length: %d",
+					i, tt.desc, l)
+			}
+		}
+	}
+}
+
+// testServe performs a single transaction using the input message, server
+// configuration, whether or not a reply is expected, and a closure which
+// acts as a HandlerFunc.
+func testServe(r *testMessage, s *Server, expectReply bool, fn func(w ResponseSender, r *Request)) (*testMessage, *recordIPv6PacketConn, error) {
+	// If caller doesn't specify a testMessage or client address
+	// for it, configure it for them
+	if r == nil {
+		r = &testMessage{}
+	}
+	if r.addr == nil {
+		r.addr = &net.UDPAddr{
+			IP: net.ParseIP("::1"),
+		}
+	}
+
+	// If caller doesn't specify Server value, configure it for
+	// them using input function as HandlerFunc.
+	if s == nil {
+		s = &Server{}
+	}
+	if s.Iface == nil {
+		s.Iface = &net.Interface{
+			Name:  "foo0",
+			Index: 0,
+		}
+	}
+	s.Handler = HandlerFunc(fn)
+
+	// Implements PacketConn to capture request/response
+	tc := &testPacketConn{
+		r: r,
+		w: &testMessage{},
+
+		// Record IPv6 control parameters
+		recordIPv6PacketConn: &recordIPv6PacketConn{
+			joined: make([]net.Addr, 0),
+			left:   make([]net.Addr, 0),
+			flags:  make(map[ipv6.ControlFlags]bool),
+		},
+	}
+
+	// Perform a single read and possibly write before returning
+	// an error on second read to close server
+	c := &oneReadPacketConn{
+		PacketConn: tc,
+
+		readDoneC:  make(chan struct{}),
+		writeDoneC: make(chan struct{}),
+	}
+
+	// If no reply is expected, this channel will never be closed,
+	// and should be closed immediately
+	if !expectReply {
+		close(c.writeDoneC)
+	}
+
+	// Handle request
+	err := s.Serve(c)
 
 	// Wait for read and write to complete
 	<-c.readDoneC
@@ -461,6 +1444,92 @@ func (c *oneReadPacketConn) WriteTo(b []byte, cm *ipv6.ControlMessage, dst net.A
 	return n, err
 }
 
+// joinedGroupsPacketConn records a Server's joined multicast groups on its
+// first read, and then issues errClosing to close the server.
+type joinedGroupsPacketConn struct {
+	PacketConn
+
+	s      *Server
+	joined []*net.IPAddr
+}
+
+// ReadFrom records the Server's joined multicast groups and returns
+// errClosing.
+func (c *joinedGroupsPacketConn) ReadFrom(b []byte) (int, *ipv6.ControlMessage, net.Addr, error) {
+	c.joined = c.s.JoinedGroups()
+	return 0, nil, nil, errClosing
+}
+
+// readFuncPacketConn invokes fn on its first read, and then issues
+// errClosing to close the server.
+type readFuncPacketConn struct {
+	PacketConn
+
+	fn func()
+}
+
+// ReadFrom invokes fn and returns errClosing.
+func (c *readFuncPacketConn) ReadFrom(b []byte) (int, *ipv6.ControlMessage, net.Addr, error) {
+	c.fn()
+	return 0, nil, nil, errClosing
+}
+
+// blockingPacketConn blocks reads until it is closed, and signals readyC
+// on its first read.
+type blockingPacketConn struct {
+	*recordIPv6PacketConn
+
+	once   sync.Once
+	readyC chan struct{}
+	closeC chan struct{}
+}
+
+// ReadFrom signals readyC, and then blocks until the connection is closed.
+func (c *blockingPacketConn) ReadFrom(b []byte) (int, *ipv6.ControlMessage, net.Addr, error) {
+	c.once.Do(func() { close(c.readyC) })
+	<-c.closeC
+	return 0, nil, nil, errors.New("read on closed connection")
+}
+
+// WriteTo is not used by blockingPacketConn.
+func (c *blockingPacketConn) WriteTo(b []byte, cm *ipv6.ControlMessage, dst net.Addr) (int, error) {
+	return 0, errors.New("write on blockingPacketConn")
+}
+
+// Close records that the connection was closed and unblocks reads.
+func (c *blockingPacketConn) Close() error {
+	close(c.closeC)
+	return c.recordIPv6PacketConn.Close()
+}
+
+// errorsPacketConn returns each of errs from successive reads, and then
+// issues errClosing to close the server.
+type errorsPacketConn struct {
+	PacketConn
+
+	errs  []error
+	reads int
+}
+
+// ReadFrom returns the next error in errs, or errClosing.
+func (c *errorsPacketConn) ReadFrom(b []byte) (int, *ipv6.ControlMessage, net.Addr, error) {
+	c.reads++
+	if len(c.errs) == 0 {
+		return 0, nil, nil, errClosing
+	}
+
+	err := c.errs[0]
+	c.errs = c.errs[1:]
+	return 0, nil, nil, err
+}
+
+// tempError is a net.Error which is temporary.
+type tempError struct{}
+
+func (tempError) Error() string   { return "temporary error" }
+func (tempError) Timeout() bool   { return false }
+func (tempError) Temporary() bool { return true }
+
 // testPacketConn captures client requests, server responses, and IPv6
 // control parameters set by the server.
 type testPacketConn struct {
@@ -494,6 +1563,26 @@ func (c *testPacketConn) WriteTo(b []byte, cm *ipv6.ControlMessage, dst net.Addr
 	return n, err
 }
 
+// joinErrorPacketConn joins n multicast groups, and then returns err from
+// each further JoinGroup.
+type joinErrorPacketConn struct {
+	PacketConn
+
+	n   int
+	err error
+}
+
+// JoinGroup joins a multicast group, or returns c.err once c.n groups have
+// been joined.
+func (c *joinErrorPacketConn) JoinGroup(ifi *net.Interface, group net.Addr) error {
+	if c.n == 0 {
+		return c.err
+	}
+	c.n--
+
+	return c.PacketConn.JoinGroup(ifi, group)
+}
+
 // recordIPv6PacketConn tracks IPv6 control parameters, such as joined and
 // left multicast groups, control flags, and whether or not the connection
 // was closed.
@@ -527,3 +1616,363 @@ func (c *recordIPv6PacketConn) SetControlMessage(cf ipv6.ControlFlags, on bool)
 	c.flags[cf] = on
 	return nil
 }
+
+// TestServerPort verifies that Server.Port parses the port from a Server's
+// configured address.
+func TestServerPort(t *testing.T) {
+	var tests = []struct {
+		desc string
+		addr string
+		port int
+	}{
+		{
+			desc: "default address",
+			port: 547,
+		},
+		{
+			desc: "default port",
+			addr: "[::]:547",
+			port: 547,
+		},
+		{
+			desc: "custom port",
+			addr: "[::1]:10547",
+			port: 10547,
+		},
+		{
+			desc: "ephemeral port",
+			addr: "[::1]:0",
+		},
+		{
+			desc: "missing port",
+			addr: "::1",
+		},
+		{
+			desc: "named port",
+			addr: "[::]:dhcpv6-server",
+		},
+		{
+			desc: "port out of range",
+			addr: "[::]:65536",
+		},
+	}
+
+	for i, tt := range tests {
+		s := &Server{
+			Addr: tt.addr,
+		}
+
+		if want, got := tt.port, s.Port(); want != got {
+			t.Errorf("[%02d] test %q, unexpected port for Server.Port(): %v != %v",
+				i, tt.desc, want, got)
+		}
+	}
+}
+
+// TestServerListenAndServeInvalidPort verifies that Server.ListenAndServe
+// rejects an address with an invalid port before binding a socket.
+func TestServerListenAndServeInvalidPort(t *testing.T) {
+	s := &Server{
+		Addr: "[::]:foo",
+	}
+
+	if err := s.ListenAndServe(); err == nil {
+		t.Fatal("expected an error for invalid port, but none occurred")
+	}
+}
+
+// Test_validateAddr verifies that validateAddr accepts only IPv6 addresses
+// with a valid port.
+func Test_validateAddr(t *testing.T) {
+	var tests = []struct {
+		desc string
+		addr string
+		ok   bool
+	}{
+		{
+			desc: "unspecified address",
+			addr: "[::]:547",
+			ok:   true,
+		},
+		{
+			desc: "empty host",
+			addr: ":547",
+			ok:   true,
+		},
+		{
+			desc: "link-local address with zone",
+			addr: "[fe80::1%eth0]:547",
+			ok:   true,
+		},
+		{
+			desc: "ephemeral port",
+			addr: "[::]:0",
+			ok:   true,
+		},
+		{
+			desc: "IPv4 address",
+			addr: "192.0.2.1:547",
+		},
+		{
+			desc: "IPv4-mapped IPv6 address",
+			addr: "[::ffff:192.0.2.1]:547",
+		},
+		{
+			desc: "hostname",
+			addr: "localhost:547",
+		},
+		{
+			desc: "empty zone",
+			addr: "[fe80::1%]:547",
+		},
+		{
+			desc: "malformed port",
+			addr: "[::]:foo",
+		},
+		{
+			desc: "missing port",
+			addr: "::1",
+		},
+	}
+
+	for i, tt := range tests {
+		err := validateAddr(tt.addr)
+		if err != nil && tt.ok {
+			t.Fatalf("[%02d] test %q, unexpected error: %v", i, tt.desc, err)
+		}
+		if err == nil && !tt.ok {
+			t.Fatalf("[%02d] test %q, expected an error, but none occurred",
+				i, tt.desc)
+		}
+	}
+}
+
+// TestServerListenAndServeIPv4Addr verifies that Server.ListenAndServe
+// rejects an IPv4 address before binding a socket.
+func TestServerListenAndServeIPv4Addr(t *testing.T) {
+	s := &Server{
+		Addr: "127.0.0.1:547",
+	}
+
+	err := s.ListenAndServe()
+	if err == nil {
+		t.Fatal("expected an error for IPv4 address, but none occurred")
+	}
+
+	if want, got := `address "127.0.0.1:547" is not an IPv6 address`, err.Error(); want != got {
+		t.Fatalf("unexpected error: %v != %v", want, got)
+	}
+}
+
+// Test_serverDUID verifies that serverDUID generates an appropriate DUID for
+// interfaces with hardware addresses of various lengths.
+func Test_serverDUID(t *testing.T) {
+	ethernet := net.HardwareAddr(bytes.Repeat([]byte{0xde}, 6))
+	infiniBand := net.HardwareAddr(bytes.Repeat([]byte{0xad}, 20))
+
+	var tests = []struct {
+		desc string
+		addr net.HardwareAddr
+		duid dhcp6opts.DUID
+		ok   bool
+	}{
+		{
+			desc: "no hardware address",
+			ok:   true,
+		},
+		{
+			desc: "Ethernet hardware address",
+			addr: ethernet,
+			duid: dhcp6opts.NewDUIDLL(1, ethernet),
+			ok:   true,
+		},
+		{
+			desc: "InfiniBand hardware address",
+			addr: infiniBand,
+			duid: dhcp6opts.NewDUIDLL(32, infiniBand),
+			ok:   true,
+		},
+		{
+			desc: "hardware address too long",
+			addr: net.HardwareAddr(bytes.Repeat([]byte{0xff}, 127)),
+		},
+	}
+
+	for i, tt := range tests {
+		duid, err := serverDUID(&net.Interface{
+			Name:         "foo0",
+			HardwareAddr: tt.addr,
+		})
+		if err != nil {
+			if tt.ok {
+				t.Fatalf("[%02d] test %q, unexpected error: %v", i, tt.desc, err)
+			}
+			continue
+		}
+		if !tt.ok {
+			t.Fatalf("[%02d] test %q, expected an error, but none occurred", i, tt.desc)
+		}
+
+		// A random DUID-UUID is generated with no hardware address
+		if tt.duid == nil {
+			d, ok := duid.(*dhcp6opts.DUIDUUID)
+			if !ok {
+				t.Fatalf("[%02d] test %q, unexpected DUID type: %T", i, tt.desc, duid)
+			}
+			if want, got := byte(0x40), d.UUID[6]&0xf0; want != got {
+				t.Fatalf("[%02d] test %q, unexpected UUID version: %#x != %#x",
+					i, tt.desc, want, got)
+			}
+			continue
+		}
+
+		if want, got := tt.duid, duid; !reflect.DeepEqual(want, got) {
+			t.Fatalf("[%02d] test %q, unexpected DUID:\n- want: %v\n-  got: %v",
+				i, tt.desc, want, got)
+		}
+	}
+}
+
+// TestServePersistDUIDPath verifies that servers configured with the same
+// PersistDUIDPath reuse the same DUID-LLT.
+func TestServePersistDUIDPath(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dhcp6server-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "duid")
+
+	serve := func() dhcp6opts.DUID {
+		s := &Server{
+			Iface: &net.Interface{
+				Name:         "foo0",
+				HardwareAddr: net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad},
+			},
+			PersistDUIDPath: path,
+		}
+
+		c := &readFuncPacketConn{
+			PacketConn: &testPacketConn{
+				recordIPv6PacketConn: &recordIPv6PacketConn{
+					flags: make(map[ipv6.ControlFlags]bool),
+				},
+			},
+			fn: func() {},
+		}
+
+		if err := s.Serve(c); err != nil {
+			t.Fatal(err)
+		}
+
+		return s.ServerID
+	}
+
+	first := serve()
+	if _, ok := first.(*dhcp6opts.DUIDLLT); !ok {
+		t.Fatalf("unexpected DUID type: %T", first)
+	}
+
+	if want, got := first, serve(); !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected DUID after restart:\n- want: %v\n-  got: %v", want, got)
+	}
+}
+
+// TestServePersistDUIDPathInvalid verifies that a server does not start if
+// its PersistDUIDPath contains an invalid DUID.
+func TestServePersistDUIDPathInvalid(t *testing.T) {
+	f, err := ioutil.TempFile("", "dhcp6server-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write([]byte{0}); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	s := &Server{
+		Iface:           &net.Interface{Name: "foo0"},
+		PersistDUIDPath: f.Name(),
+	}
+
+	if err := s.Serve(nil); err == nil {
+		t.Fatal("expected an error for invalid persistent DUID, but none occurred")
+	}
+}
+
+// repeatPacketConn returns a copy of b and control message cm from each
+// read, n times, and then issues errClosing to close the server.
+type repeatPacketConn struct {
+	PacketConn
+
+	b  []byte
+	cm *ipv6.ControlMessage
+	n  int
+}
+
+// ReadFrom copies c.b into b until c.n reads have been performed.
+func (c *repeatPacketConn) ReadFrom(b []byte) (int, *ipv6.ControlMessage, net.Addr, error) {
+	if c.n == 0 {
+		return 0, nil, nil, errClosing
+	}
+	c.n--
+
+	return copy(b, c.b), c.cm, &net.UDPAddr{IP: net.ParseIP("::1")}, nil
+}
+
+// BenchmarkServe measures the cost of reading and handling a minimal
+// request with a Server.
+func BenchmarkServe(b *testing.B) {
+	benchmarkServe(b, make(dhcp6.Options))
+}
+
+// BenchmarkServeLargeRequest measures the cost of reading and handling a
+// request which nearly fills the Server's read buffer.
+func BenchmarkServeLargeRequest(b *testing.B) {
+	o := make(dhcp6.Options)
+	o.AddRaw(dhcp6.OptionVendorOpts, make([]byte, 1200))
+
+	benchmarkServe(b, o)
+}
+
+// benchmarkServe measures the cost of reading and handling Solicit requests
+// containing options o with a Server.
+func benchmarkServe(b *testing.B, o dhcp6.Options) {
+	p := &dhcp6.Packet{
+		MessageType:   dhcp6.MessageTypeSolicit,
+		TransactionID: [3]byte{1, 2, 3},
+		Options:       o,
+	}
+	pb, err := p.MarshalBinary()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	s := &Server{
+		Iface:    &net.Interface{Name: "foo0", Index: 0},
+		ServerID: dhcp6opts.NewDUIDLL(1, net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad}),
+		Handler:  HandlerFunc(func(w ResponseSender, r *Request) {}),
+	}
+
+	c := &repeatPacketConn{
+		PacketConn: &testPacketConn{
+			recordIPv6PacketConn: &recordIPv6PacketConn{
+				flags: make(map[ipv6.ControlFlags]bool),
+			},
+		},
+		b: pb,
+		n: b.N,
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	if err := s.Serve(c); err != nil {
+		b.Fatal(err)
+	}
+}
@@ -0,0 +1,160 @@
+package dhcp6server
+
+import (
+	"encoding"
+	"log"
+	"net"
+	"time"
+
+	"github.com/mdlayher/dhcp6"
+	"github.com/mdlayher/dhcp6/dhcp6opts"
+)
+
+// StaticInfoHandler is a Handler which answers Information-request messages
+// with a static set of configuration parameters, acting as a stateless DHCP
+// server, as described in RFC 3736.
+//
+// Only options which a client requests using the Option Request Option are
+// included in a Reply, and empty parameters are never sent.  Requests with
+// any other message type are ignored.
+//
+// When serving requests with a Server, the Server ID and Client ID options
+// are added to each Reply automatically.
+//
+// NewStaticInfoHandler should be used to create a StaticInfoHandler, so that
+// invalid configuration is reported before any requests are served.
+type StaticInfoHandler struct {
+	// DNSServers specifies the IPv6 addresses of recursive DNS servers,
+	// in order of preference, as described in RFC 3646, Section 3.
+	DNSServers []net.IP
+
+	// DomainSearchList specifies the domain names a client should use
+	// when resolving hostnames, as described in RFC 3646, Section 4.
+	DomainSearchList []string
+
+	// NTPServers specifies the IPv6 addresses of NTP servers, in order
+	// of preference, as described in RFC 5908, Section 4.
+	NTPServers []net.IP
+
+	// InformationRefreshTime specifies how long a client should wait
+	// before refreshing the configuration it receives, as described in
+	// RFC 4242, Section 3.  Values less than 10 minutes are raised to 10
+	// minutes, the minimum permitted by the RFC.
+	InformationRefreshTime time.Duration
+
+	// ErrorLog is an optional logger which is used to report configuration
+	// which cannot be sent to a client.  If ErrorLog is nil, logging goes
+	// to os.Stderr via the log package's standard logger.
+	ErrorLog *log.Logger
+}
+
+// staticInfoCodes are the option codes which a StaticInfoHandler may send.
+var staticInfoCodes = []dhcp6.OptionCode{
+	dhcp6.OptionDNSServers,
+	dhcp6.OptionDomainList,
+	dhcp6.OptionNTPServer,
+	dhcp6.OptionInformationRefreshTime,
+}
+
+// NewStaticInfoHandler creates a StaticInfoHandler which serves the input
+// DNS servers, domain search list, NTP servers, and information refresh time.
+// Empty parameters are never sent.
+//
+// If any parameter cannot be encoded as a DHCPv6 option, such as a DNS or NTP
+// server address which is not an IPv6 address, its error is returned.
+func NewStaticInfoHandler(dns []net.IP, domains []string, ntp []net.IP, irt time.Duration) (*StaticInfoHandler, error) {
+	h := &StaticInfoHandler{
+		DNSServers:             dns,
+		DomainSearchList:       domains,
+		NTPServers:             ntp,
+		InformationRefreshTime: irt,
+	}
+
+	for _, code := range staticInfoCodes {
+		v := h.value(code)
+		if v == nil {
+			continue
+		}
+
+		if _, err := v.MarshalBinary(); err != nil {
+			return nil, err
+		}
+	}
+
+	return h, nil
+}
+
+// irtMinimum is the smallest information refresh time a server may send,
+// as described in RFC 4242, Section 3.
+const irtMinimum = 600 * time.Second
+
+// ServeDHCP implements Handler for StaticInfoHandler.
+func (h *StaticInfoHandler) ServeDHCP(w ResponseSender, r *Request) {
+	if r.MessageType != dhcp6.MessageTypeInformationRequest {
+		return
+	}
+
+	// Clients must include an Option Request Option in an
+	// Information-request, but if it is missing or malformed, a Reply
+	// with no configuration parameters is still sent.
+	oro, _ := dhcp6opts.GetOptionRequest(r.Options)
+
+	o := w.Options()
+	for _, code := range oro {
+		// Ignore duplicate requests for the same option.
+		if _, ok := o[code]; ok {
+			continue
+		}
+
+		v := h.value(code)
+		if v == nil {
+			continue
+		}
+
+		// Invalid configuration cannot be sent to a client.
+		if err := o.Add(code, v); err != nil {
+			h.logf("%s: invalid %s configuration: %v", r.RemoteAddr, code, err)
+			return
+		}
+	}
+
+	_, _ = w.Send(dhcp6.MessageTypeReply)
+}
+
+// value returns the configured value for the option specified by code, or
+// nil if the option is not configured or not supported.
+func (h *StaticInfoHandler) value(code dhcp6.OptionCode) encoding.BinaryMarshaler {
+	switch code {
+	case dhcp6.OptionDNSServers:
+		if len(h.DNSServers) > 0 {
+			return dhcp6opts.IPs(h.DNSServers)
+		}
+	case dhcp6.OptionDomainList:
+		if len(h.DomainSearchList) > 0 {
+			return dhcp6opts.DomainSearchList(h.DomainSearchList)
+		}
+	case dhcp6.OptionNTPServer:
+		if len(h.NTPServers) > 0 {
+			return dhcp6opts.NTPServers(h.NTPServers)
+		}
+	case dhcp6.OptionInformationRefreshTime:
+		if irt := h.InformationRefreshTime; irt > 0 {
+			if irt < irtMinimum {
+				irt = irtMinimum
+			}
+			return dhcp6opts.InformationRefreshTime(irt)
+		}
+	}
+
+	return nil
+}
+
+// logf logs a message using the handler's ErrorLog logger, or the log
+// package standard logger, if ErrorLog is nil.
+func (h *StaticInfoHandler) logf(format string, args ...interface{}) {
+	if h.ErrorLog != nil {
+		h.ErrorLog.Printf(format, args...)
+	} else {
+		log.Printf(format, args...)
+	}
+}
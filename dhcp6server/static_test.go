@@ -0,0 +1,242 @@
+package dhcp6server_test
+
+import (
+	"bytes"
+	"log"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mdlayher/dhcp6"
+	"github.com/mdlayher/dhcp6/dhcp6opts"
+	"github.com/mdlayher/dhcp6/dhcp6server"
+	"github.com/mdlayher/dhcp6/dhcp6test"
+)
+
+// TestStaticInfoHandler verifies that StaticInfoHandler replies to an
+// Information-request with only the options requested by a client.
+func TestStaticInfoHandler(t *testing.T) {
+	h := &dhcp6server.StaticInfoHandler{
+		DNSServers:       []net.IP{net.ParseIP("2001:db8::53")},
+		DomainSearchList: []string{"example.com"},
+		NTPServers:       []net.IP{net.ParseIP("2001:db8::123")},
+
+		InformationRefreshTime: time.Minute,
+	}
+
+	var tests = []struct {
+		desc  string
+		mt    dhcp6.MessageType
+		oro   dhcp6opts.OptionRequestOption
+		codes []dhcp6.OptionCode
+		reply bool
+	}{
+		{
+			desc: "Solicit ignored",
+			mt:   dhcp6.MessageTypeSolicit,
+			oro:  dhcp6opts.OptionRequestOption{dhcp6.OptionDNSServers},
+		},
+		{
+			desc:  "no options requested",
+			mt:    dhcp6.MessageTypeInformationRequest,
+			reply: true,
+		},
+		{
+			desc:  "DNS servers requested",
+			mt:    dhcp6.MessageTypeInformationRequest,
+			oro:   dhcp6opts.OptionRequestOption{dhcp6.OptionDNSServers},
+			codes: []dhcp6.OptionCode{dhcp6.OptionDNSServers},
+			reply: true,
+		},
+		{
+			desc: "all options requested, with unknown option",
+			mt:   dhcp6.MessageTypeInformationRequest,
+			oro: dhcp6opts.OptionRequestOption{
+				dhcp6.OptionNTPServer,
+				dhcp6.OptionDomainList,
+				dhcp6.OptionBootFileURL,
+				dhcp6.OptionInformationRefreshTime,
+				dhcp6.OptionDNSServers,
+			},
+			codes: []dhcp6.OptionCode{
+				dhcp6.OptionDNSServers,
+				dhcp6.OptionDomainList,
+				dhcp6.OptionInformationRefreshTime,
+				dhcp6.OptionNTPServer,
+			},
+			reply: true,
+		},
+	}
+
+	for i, tt := range tests {
+		o := make(dhcp6.Options)
+		if tt.oro != nil {
+			if err := o.Add(dhcp6.OptionORO, tt.oro); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		r := &dhcp6server.Request{
+			MessageType: tt.mt,
+			Options:     o,
+		}
+
+		w := dhcp6test.NewRecorder(r.TransactionID)
+		h.ServeDHCP(w, r)
+
+		if !tt.reply {
+			if mt := w.MessageType; mt != dhcp6.MessageType(0) {
+				t.Fatalf("[%02d] test %q, expected no reply, but got message type: %v",
+					i, tt.desc, mt)
+			}
+			continue
+		}
+
+		if want, got := dhcp6.MessageTypeReply, w.MessageType; want != got {
+			t.Fatalf("[%02d] test %q, unexpected message type: %v != %v",
+				i, tt.desc, want, got)
+		}
+
+		var codes []dhcp6.OptionCode
+		for _, code := range []dhcp6.OptionCode{
+			dhcp6.OptionDNSServers,
+			dhcp6.OptionDomainList,
+			dhcp6.OptionBootFileURL,
+			dhcp6.OptionInformationRefreshTime,
+			dhcp6.OptionNTPServer,
+		} {
+			if _, ok := w.Options()[code]; ok {
+				codes = append(codes, code)
+			}
+		}
+
+		if want, got := tt.codes, codes; !reflect.DeepEqual(want, got) {
+			t.Fatalf("[%02d] test %q, unexpected reply options:\n- want: %v\n-  got: %v",
+				i, tt.desc, want, got)
+		}
+	}
+
+	// Verify the values of the returned options.
+	o := make(dhcp6.Options)
+	if err := o.Add(dhcp6.OptionORO, dhcp6opts.OptionRequestOption{
+		dhcp6.OptionDNSServers,
+		dhcp6.OptionNTPServer,
+		dhcp6.OptionInformationRefreshTime,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	w := dhcp6test.NewRecorder([3]byte{})
+	h.ServeDHCP(w, &dhcp6server.Request{
+		MessageType: dhcp6.MessageTypeInformationRequest,
+		Options:     o,
+	})
+
+	dns, err := dhcp6opts.GetDNSServers(w.Options())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := dhcp6opts.IPs(h.DNSServers), dns; !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected DNS servers:\n- want: %v\n-  got: %v", want, got)
+	}
+
+	ntp, err := dhcp6opts.GetNTPServers(w.Options())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := dhcp6opts.NTPServers(h.NTPServers), ntp; !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected NTP servers:\n- want: %v\n-  got: %v", want, got)
+	}
+
+	// The information refresh time is raised to the RFC 4242 minimum.
+	irt, err := dhcp6opts.GetInformationRefreshTime(w.Options())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := dhcp6opts.InformationRefreshTime(10*time.Minute), irt; want != got {
+		t.Fatalf("unexpected information refresh time: %v != %v", want, got)
+	}
+}
+
+// TestNewStaticInfoHandler verifies that NewStaticInfoHandler reports
+// configuration which cannot be sent to a client.
+func TestNewStaticInfoHandler(t *testing.T) {
+	var tests = []struct {
+		desc    string
+		dns     []net.IP
+		domains []string
+		ntp     []net.IP
+		err     error
+	}{
+		{
+			desc: "no configuration",
+		},
+		{
+			desc:    "valid configuration",
+			dns:     []net.IP{net.ParseIP("2001:db8::53")},
+			domains: []string{"example.com"},
+			ntp:     []net.IP{net.ParseIP("2001:db8::123")},
+		},
+		{
+			desc: "IPv4 DNS server",
+			dns:  []net.IP{net.IPv4(192, 0, 2, 53)},
+			err:  dhcp6opts.ErrInvalidIP,
+		},
+		{
+			desc:    "invalid domain",
+			domains: []string{"a..com"},
+			err:     dhcp6opts.ErrInvalidDomainName,
+		},
+		{
+			desc: "nil NTP server",
+			ntp:  []net.IP{nil},
+			err:  dhcp6opts.ErrInvalidIP,
+		},
+	}
+
+	for i, tt := range tests {
+		h, err := dhcp6server.NewStaticInfoHandler(tt.dns, tt.domains, tt.ntp, time.Hour)
+		if want, got := tt.err, err; want != got {
+			t.Fatalf("[%02d] test %q, unexpected error: %v != %v",
+				i, tt.desc, want, got)
+		}
+		if err != nil {
+			continue
+		}
+
+		if want, got := time.Hour, h.InformationRefreshTime; want != got {
+			t.Fatalf("[%02d] test %q, unexpected information refresh time: %v != %v",
+				i, tt.desc, want, got)
+		}
+	}
+}
+
+// TestStaticInfoHandlerInvalidConfiguration verifies that StaticInfoHandler
+// logs and does not reply when its configuration cannot be sent to a client.
+func TestStaticInfoHandlerInvalidConfiguration(t *testing.T) {
+	var buf bytes.Buffer
+	h := &dhcp6server.StaticInfoHandler{
+		NTPServers: []net.IP{net.IPv4(192, 0, 2, 123)},
+		ErrorLog:   log.New(&buf, "", 0),
+	}
+
+	o := make(dhcp6.Options)
+	if err := o.Add(dhcp6.OptionORO, dhcp6opts.OptionRequestOption{dhcp6.OptionNTPServer}); err != nil {
+		t.Fatal(err)
+	}
+
+	w := dhcp6test.NewRecorder([3]byte{})
+	h.ServeDHCP(w, &dhcp6server.Request{
+		MessageType: dhcp6.MessageTypeInformationRequest,
+		Options:     o,
+	})
+
+	if mt := w.MessageType; mt != dhcp6.MessageType(0) {
+		t.Fatalf("expected no reply, but got message type: %v", mt)
+	}
+	if !strings.Contains(buf.String(), dhcp6opts.ErrInvalidIP.Error()) {
+		t.Fatalf("expected invalid configuration to be logged, but got: %q", buf.String())
+	}
+}
@@ -2,7 +2,10 @@ package dhcp6
 
 import (
 	"encoding"
+	"encoding/hex"
+	"math"
 	"sort"
+	"sync"
 
 	"github.com/mdlayher/dhcp6/internal/buffer"
 )
@@ -11,6 +14,10 @@ import (
 //
 // Its methods can be used to easily check for additional information from a
 // packet. Get and GetOne should be used to access data from Options.
+//
+// A zero-length option, such as OptionRapidCommit, is stored as a single
+// empty value.  A key which is present with no values is treated in the same
+// way, and is encoded as exactly one zero-length option.
 type Options map[OptionCode][][]byte
 
 // Add adds a new OptionCode key and BinaryMarshaler struct's bytes to the
@@ -34,10 +41,78 @@ func (o Options) Add(key OptionCode, value encoding.BinaryMarshaler) error {
 
 // AddRaw adds a new OptionCode key and raw value byte slice to the
 // Options map.
+//
+// AddRaw is useful when the exact bytes of an option value are already
+// available, such as a value copied from another packet using Get or GetOne.
+// The value is stored as-is, without validation.  A nil or empty value adds
+// a zero-length option, in the same way that Add does with a nil
+// BinaryMarshaler.
 func (o Options) AddRaw(key OptionCode, value []byte) {
 	o[key] = append(o[key], value)
 }
 
+// Del removes all values stored under the OptionCode key from the Options
+// map.  If key is not present, Del does nothing.
+func (o Options) Del(key OptionCode) {
+	delete(o, key)
+}
+
+// Set replaces any values stored under the OptionCode key with a single
+// BinaryMarshaler struct's bytes.  As with Add, a nil value stores a single
+// zero-length option.
+//
+// If value cannot be marshaled, its error is returned and the Options map
+// is not modified.
+func (o Options) Set(key OptionCode, value encoding.BinaryMarshaler) error {
+	var b []byte
+	if value != nil {
+		var err error
+		b, err = value.MarshalBinary()
+		if err != nil {
+			return err
+		}
+	}
+
+	o[key] = [][]byte{b}
+	return nil
+}
+
+// Codes returns the option codes present in the Options map, sorted in
+// ascending order.  Each option code is returned only once, even if more
+// than one value is stored for it.
+func (o Options) Codes() []OptionCode {
+	return []OptionCode(o.sortedCodes())
+}
+
+// Rename moves all values stored under the old OptionCode key to the new
+// OptionCode key.  If values are already stored under new, the values from
+// old are appended to them.  Rename is useful for compatibility shims when
+// a vendor migrates from one option code to another.
+//
+// If old is not present, or old and new are the same, Rename does nothing.
+func (o Options) Rename(old, new OptionCode) {
+	v, ok := o[old]
+	if !ok || old == new {
+		return
+	}
+
+	// Preserve a zero-length option with no values as a single value,
+	// so it is not lost when appending.
+	if len(v) == 0 {
+		v = [][]byte{{}}
+	}
+
+	o[new] = append(o[new], v...)
+	delete(o, old)
+}
+
+// RemoveStatusCodes removes all top-level Status Code options from the
+// Options map.  Status Code options encapsulated within other options,
+// such as IA_NA, are not modified.
+func (o Options) RemoveStatusCodes() {
+	delete(o, OptionStatusCode)
+}
+
 // Get attempts to retrieve all values specified by an OptionCode key.
 //
 // If a value is found, get returns a non-nil [][]byte and nil. If it is not
@@ -75,12 +150,67 @@ func (o Options) GetOne(key OptionCode) ([]byte, error) {
 	return vv[0], nil
 }
 
+var (
+	decodersMu sync.RWMutex
+	decoders   = make(map[OptionCode]func() encoding.BinaryUnmarshaler)
+)
+
+// RegisterDecoder registers a function which allocates a new value for an
+// OptionCode, so that the value can be decoded using Options.Decode.
+// RegisterDecoder is typically used to decode custom or vendor-specific
+// options which are not otherwise supported.
+//
+// If a decoder is already registered for an OptionCode, it is replaced.
+func RegisterDecoder(key OptionCode, factory func() encoding.BinaryUnmarshaler) {
+	decodersMu.Lock()
+	defer decodersMu.Unlock()
+
+	decoders[key] = factory
+}
+
+// Decode retrieves the single value specified by an OptionCode key, and
+// decodes it using a new value allocated by the decoder registered for the
+// key with RegisterDecoder.
+//
+// If no decoder is registered for the key, ErrNoDecoder is returned.  If the
+// key is not present, or has more than one value, Decode returns the same
+// errors as GetOne.
+func (o Options) Decode(key OptionCode) (encoding.BinaryUnmarshaler, error) {
+	decodersMu.RLock()
+	factory, ok := decoders[key]
+	decodersMu.RUnlock()
+	if !ok {
+		return nil, ErrNoDecoder
+	}
+
+	v, err := o.GetOne(key)
+	if err != nil {
+		return nil, err
+	}
+
+	u := factory()
+	if err := u.UnmarshalBinary(v); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
 // MarshalBinary allocates a buffer and writes options in their DHCPv6 binary
 // format into the buffer.
+//
+// If any option value is longer than 65535 bytes, ErrOptionTooLarge is
+// returned.
 func (o Options) MarshalBinary() ([]byte, error) {
 	b := buffer.New(nil)
 	for _, code := range o.sortedCodes() {
-		for _, data := range o[code] {
+		// Get treats a key with no values as a single zero-length value,
+		// so that zero-length options are always written exactly once.
+		values, _ := o.Get(code)
+		for _, data := range values {
+			if len(data) > math.MaxUint16 {
+				return nil, ErrOptionTooLarge
+			}
+
 			// 2 bytes: option code
 			b.Write16(uint16(code))
 
@@ -94,6 +224,42 @@ func (o Options) MarshalBinary() ([]byte, error) {
 	return b.Data(), nil
 }
 
+// Hex returns the DHCPv6 binary format of the Options map, encoded as a
+// hexadecimal string.  Options are written in ascending order of their
+// option codes, so the same Options always produce the same string.
+//
+// Hex is useful for writing test fixtures, and can be reversed using
+// ParseOptionsHex.  If the Options cannot be marshaled, the error from
+// MarshalBinary is returned.
+func (o Options) Hex() (string, error) {
+	b, err := o.MarshalBinary()
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+// ParseOptionsHex parses an Options map from a hexadecimal string containing
+// options in their DHCPv6 binary format, such as one produced by Options.Hex.
+//
+// If s is not a valid hexadecimal string, an error from package encoding/hex
+// is returned.  If the options data is malformed, ErrInvalidOptions is
+// returned.
+func ParseOptionsHex(s string) (Options, error) {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+
+	var o Options
+	if err := o.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+
+	return o, nil
+}
+
 // UnmarshalBinary fills opts with option codes and corresponding values from
 // an input byte slice.
 //
@@ -2,6 +2,8 @@ package dhcp6
 
 import (
 	"bytes"
+	"encoding"
+	"errors"
 	"reflect"
 	"testing"
 )
@@ -84,6 +86,76 @@ func TestOptionsAddRaw(t *testing.T) {
 	}
 }
 
+// TestOptionsAddRawCopiedValue verifies that a raw value copied from one
+// packet's Options using Options.AddRaw is marshaled identically in another
+// packet.
+func TestOptionsAddRawCopiedValue(t *testing.T) {
+	// DUID-LL, hardware type 1, hardware address 00:01:00:01:00:01
+	duid := []byte{0, 3, 0, 1, 0, 1, 0, 1, 0, 1}
+
+	req := &Packet{
+		MessageType:   MessageTypeSolicit,
+		TransactionID: [3]byte{0, 1, 2},
+		Options:       make(Options),
+	}
+	req.Options.AddRaw(OptionClientID, duid)
+
+	b, err := req.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := new(Packet)
+	if err := p.UnmarshalBinary(b); err != nil {
+		t.Fatal(err)
+	}
+
+	cID, err := p.Options.GetOne(OptionClientID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	o := make(Options)
+	o.AddRaw(OptionClientID, cID)
+
+	ob, err := o.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := append([]byte{0, 1, 0, byte(len(duid))}, duid...)
+	if got := ob; !bytes.Equal(want, got) {
+		t.Fatalf("unexpected Options bytes:\n- want: %v\n-  got: %v", want, got)
+	}
+}
+
+// TestOptionsAddRawZeroLength verifies that Options.AddRaw and Options.Add
+// produce identical zero-length options.
+func TestOptionsAddRawZeroLength(t *testing.T) {
+	add := make(Options)
+	if err := add.Add(OptionRapidCommit, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	addRaw := make(Options)
+	addRaw.AddRaw(OptionRapidCommit, nil)
+
+	addRawEmpty := make(Options)
+	addRawEmpty.AddRaw(OptionRapidCommit, []byte{})
+
+	want := []byte{0, 14, 0, 0}
+	for _, o := range []Options{add, addRaw, addRawEmpty} {
+		b, err := o.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := b; !bytes.Equal(want, got) {
+			t.Fatalf("unexpected Options bytes:\n- want: %v\n-  got: %v", want, got)
+		}
+	}
+}
+
 // TestOptionsGet verifies that Options.Get correctly selects the first value
 // for a given key, if the value is not empty in an Options map.
 func TestOptionsGet(t *testing.T) {
@@ -153,6 +225,436 @@ func TestOptionsGet(t *testing.T) {
 	}
 }
 
+// testCustomOption is a custom option type used to test decoders registered
+// with RegisterDecoder.
+type testCustomOption struct {
+	Value string
+}
+
+func (o *testCustomOption) UnmarshalBinary(b []byte) error {
+	if len(b) == 0 {
+		return ErrInvalidOptions
+	}
+
+	o.Value = string(b)
+	return nil
+}
+
+// TestOptionsDecode verifies that Options.Decode uses decoders registered
+// with RegisterDecoder to decode option values.
+func TestOptionsDecode(t *testing.T) {
+	const (
+		registered   OptionCode = 65000
+		unregistered OptionCode = 65001
+	)
+
+	RegisterDecoder(registered, func() encoding.BinaryUnmarshaler {
+		return new(testCustomOption)
+	})
+
+	var tests = []struct {
+		desc    string
+		options Options
+		key     OptionCode
+		value   encoding.BinaryUnmarshaler
+		err     error
+	}{
+		{
+			desc: "no decoder registered",
+			options: Options{
+				unregistered: [][]byte{[]byte("foo")},
+			},
+			key: unregistered,
+			err: ErrNoDecoder,
+		},
+		{
+			desc: "value not present in Options map",
+			key:  registered,
+			err:  ErrOptionNotPresent,
+		},
+		{
+			desc: "value present in Options map, with multiple values",
+			options: Options{
+				registered: [][]byte{[]byte("foo"), []byte("bar")},
+			},
+			key: registered,
+			err: ErrInvalidPacket,
+		},
+		{
+			desc: "value present in Options map, but malformed",
+			options: Options{
+				registered: [][]byte{{}},
+			},
+			key: registered,
+			err: ErrInvalidOptions,
+		},
+		{
+			desc: "value present in Options map",
+			options: Options{
+				registered: [][]byte{[]byte("foo")},
+			},
+			key: registered,
+			value: &testCustomOption{
+				Value: "foo",
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		value, err := tt.options.Decode(tt.key)
+		if err != nil {
+			if want, got := tt.err, err; want != got {
+				t.Errorf("[%02d] test %q, unexpected err for Options.Decode(%v): %v != %v",
+					i, tt.desc, tt.key, want, got)
+			}
+			continue
+		}
+
+		if want, got := tt.value, value; !reflect.DeepEqual(want, got) {
+			t.Errorf("[%02d] test %q, unexpected value for Options.Decode(%v):\n- want: %v\n-  got: %v",
+				i, tt.desc, tt.key, want, got)
+		}
+	}
+}
+
+// TestOptionsCodes verifies that Options.Codes returns the sorted, unique
+// option codes present in an Options map.
+func TestOptionsCodes(t *testing.T) {
+	var tests = []struct {
+		desc    string
+		options Options
+		codes   []OptionCode
+	}{
+		{
+			desc: "no options",
+		},
+		{
+			desc: "one option",
+			options: Options{
+				OptionClientID: [][]byte{{1}},
+			},
+			codes: []OptionCode{OptionClientID},
+		},
+		{
+			desc: "duplicate options",
+			options: Options{
+				OptionIANA:        [][]byte{{1}, {2}, {3}},
+				OptionRapidCommit: [][]byte{{}},
+				OptionClientID:    [][]byte{{1}},
+				OptionORO:         [][]byte{{0, 23}},
+				OptionStatusCode:  [][]byte{{0, 0}, {0, 1}},
+			},
+			codes: []OptionCode{
+				OptionClientID,
+				OptionIANA,
+				OptionORO,
+				OptionStatusCode,
+				OptionRapidCommit,
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		if want, got := tt.codes, tt.options.Codes(); !reflect.DeepEqual(want, got) {
+			t.Fatalf("[%02d] test %q, unexpected option codes:\n- want: %v\n-  got: %v",
+				i, tt.desc, want, got)
+		}
+	}
+}
+
+// TestOptionsRename verifies that Options.Rename moves values from one
+// option code to another.
+func TestOptionsRename(t *testing.T) {
+	var tests = []struct {
+		desc     string
+		options  Options
+		old, new OptionCode
+		want     Options
+	}{
+		{
+			desc: "old not present",
+			options: Options{
+				1: [][]byte{{1}},
+			},
+			old: 2,
+			new: 3,
+			want: Options{
+				1: [][]byte{{1}},
+			},
+		},
+		{
+			desc: "old and new are the same",
+			options: Options{
+				1: [][]byte{{1}},
+			},
+			old: 1,
+			new: 1,
+			want: Options{
+				1: [][]byte{{1}},
+			},
+		},
+		{
+			desc: "move values to new code",
+			options: Options{
+				1:     [][]byte{{1}, {2}},
+				65000: [][]byte{{3}},
+			},
+			old: 65000,
+			new: 2,
+			want: Options{
+				1: [][]byte{{1}, {2}},
+				2: [][]byte{{3}},
+			},
+		},
+		{
+			desc: "append values to existing code",
+			options: Options{
+				1: [][]byte{{1}},
+				2: [][]byte{{2}, {3}},
+			},
+			old: 2,
+			new: 1,
+			want: Options{
+				1: [][]byte{{1}, {2}, {3}},
+			},
+		},
+		{
+			desc: "zero-length option with no values",
+			options: Options{
+				1: [][]byte{{1}},
+				2: [][]byte{},
+			},
+			old: 2,
+			new: 1,
+			want: Options{
+				1: [][]byte{{1}, {}},
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		tt.options.Rename(tt.old, tt.new)
+
+		if want, got := tt.want, tt.options; !reflect.DeepEqual(want, got) {
+			t.Fatalf("[%02d] test %q, unexpected Options:\n- want: %v\n-  got: %v",
+				i, tt.desc, want, got)
+		}
+	}
+}
+
+// testMarshaler is a BinaryMarshaler which returns fixed bytes or an error.
+type testMarshaler struct {
+	b   []byte
+	err error
+}
+
+func (m *testMarshaler) MarshalBinary() ([]byte, error) {
+	return m.b, m.err
+}
+
+// TestOptionsDel verifies that Options.Del removes all values stored under
+// an option code.
+func TestOptionsDel(t *testing.T) {
+	var tests = []struct {
+		desc    string
+		options Options
+		key     OptionCode
+		want    Options
+	}{
+		{
+			desc: "key not present",
+			options: Options{
+				1: [][]byte{{1}},
+			},
+			key: 2,
+			want: Options{
+				1: [][]byte{{1}},
+			},
+		},
+		{
+			desc: "remove all values",
+			options: Options{
+				1: [][]byte{{1}},
+				2: [][]byte{{2}, {3}},
+			},
+			key: 2,
+			want: Options{
+				1: [][]byte{{1}},
+			},
+		},
+		{
+			desc: "remove zero-length option",
+			options: Options{
+				OptionRapidCommit: [][]byte{{}},
+			},
+			key:  OptionRapidCommit,
+			want: Options{},
+		},
+	}
+
+	for i, tt := range tests {
+		tt.options.Del(tt.key)
+
+		if want, got := tt.want, tt.options; !reflect.DeepEqual(want, got) {
+			t.Fatalf("[%02d] test %q, unexpected Options:\n- want: %v\n-  got: %v",
+				i, tt.desc, want, got)
+		}
+	}
+}
+
+// TestOptionsSet verifies that Options.Set replaces any existing values for
+// an option code with a single value.
+func TestOptionsSet(t *testing.T) {
+	errMarshal := errors.New("marshal error")
+
+	var tests = []struct {
+		desc    string
+		options Options
+		key     OptionCode
+		value   encoding.BinaryMarshaler
+		want    Options
+		err     error
+	}{
+		{
+			desc:    "key not present",
+			options: Options{},
+			key:     1,
+			value:   &testMarshaler{b: []byte{1}},
+			want: Options{
+				1: [][]byte{{1}},
+			},
+		},
+		{
+			desc: "replace multiple values",
+			options: Options{
+				1: [][]byte{{1}, {2}},
+				2: [][]byte{{3}},
+			},
+			key:   1,
+			value: &testMarshaler{b: []byte{4}},
+			want: Options{
+				1: [][]byte{{4}},
+				2: [][]byte{{3}},
+			},
+		},
+		{
+			desc: "nil value stores zero-length option",
+			options: Options{
+				OptionRapidCommit: [][]byte{{}, {}},
+			},
+			key: OptionRapidCommit,
+			want: Options{
+				OptionRapidCommit: [][]byte{nil},
+			},
+		},
+		{
+			desc: "marshal error leaves options unchanged",
+			options: Options{
+				1: [][]byte{{1}},
+			},
+			key:   1,
+			value: &testMarshaler{err: errMarshal},
+			want: Options{
+				1: [][]byte{{1}},
+			},
+			err: errMarshal,
+		},
+	}
+
+	for i, tt := range tests {
+		if want, got := tt.err, tt.options.Set(tt.key, tt.value); want != got {
+			t.Fatalf("[%02d] test %q, unexpected error: %v != %v",
+				i, tt.desc, want, got)
+		}
+
+		if want, got := tt.want, tt.options; !reflect.DeepEqual(want, got) {
+			t.Fatalf("[%02d] test %q, unexpected Options:\n- want: %v\n-  got: %v",
+				i, tt.desc, want, got)
+		}
+	}
+}
+
+// TestOptionsRemoveStatusCodes verifies that Options.RemoveStatusCodes
+// removes all top-level Status Code options, and leaves other options intact.
+func TestOptionsRemoveStatusCodes(t *testing.T) {
+	o := Options{
+		OptionStatusCode: [][]byte{{0, 0}, {0, 1, 'f', 'o', 'o'}},
+		OptionIANA:       [][]byte{{0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 13, 0, 2, 0, 2}},
+	}
+
+	o.RemoveStatusCodes()
+
+	if _, err := o.Get(OptionStatusCode); err != ErrOptionNotPresent {
+		t.Fatalf("unexpected error for Status Code after removal: %v", err)
+	}
+
+	want := Options{
+		OptionIANA: [][]byte{{0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 13, 0, 2, 0, 2}},
+	}
+	if got := o; !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected Options:\n- want: %v\n-  got: %v", want, got)
+	}
+}
+
+// TestOptionsMarshalBinaryZeroLength verifies that Options.MarshalBinary
+// writes exactly one zero-length option, regardless of how a zero-length
+// option is stored in the Options map.
+func TestOptionsMarshalBinaryZeroLength(t *testing.T) {
+	var tests = []struct {
+		desc    string
+		options Options
+	}{
+		{
+			desc: "no values",
+			options: Options{
+				OptionRapidCommit: [][]byte{},
+			},
+		},
+		{
+			desc: "nil values",
+			options: Options{
+				OptionRapidCommit: nil,
+			},
+		},
+		{
+			desc: "one nil value",
+			options: Options{
+				OptionRapidCommit: [][]byte{nil},
+			},
+		},
+		{
+			desc: "one empty value",
+			options: Options{
+				OptionRapidCommit: [][]byte{{}},
+			},
+		},
+		{
+			desc:    "added with Add",
+			options: addRapidCommit(make(Options)),
+		},
+	}
+
+	want := []byte{0, byte(OptionRapidCommit), 0, 0}
+
+	for i, tt := range tests {
+		b, err := tt.options.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := b; !bytes.Equal(want, got) {
+			t.Fatalf("[%02d] test %q, unexpected Options bytes:\n- want: %v\n-  got: %v",
+				i, tt.desc, want, got)
+		}
+	}
+}
+
+// addRapidCommit adds a zero-length OptionRapidCommit to o using Add.
+func addRapidCommit(o Options) Options {
+	_ = o.Add(OptionRapidCommit, nil)
+	return o
+}
+
 // Test_parseOptions verifies that parseOptions parses correct option values
 // from a slice of bytes, and that it returns an empty Options map if the byte
 // slice cannot contain options.
@@ -247,3 +749,110 @@ func Test_parseOptions(t *testing.T) {
 		}
 	}
 }
+
+// TestOptionsZeroLengthRoundTrip verifies that zero-length options, whose
+// presence alone is meaningful, are preserved when Options are marshaled
+// and parsed again.
+func TestOptionsZeroLengthRoundTrip(t *testing.T) {
+	o := make(Options)
+	o.AddRaw(OptionClientID, []byte{0, 3, 0, 1, 1, 2, 3, 4, 5, 6})
+	_ = o.Add(OptionRapidCommit, nil)
+	_ = o.Add(OptionReconfAccept, nil)
+
+	b, err := o.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got Options
+	if err := (&got).UnmarshalBinary(b); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, code := range []OptionCode{OptionRapidCommit, OptionReconfAccept} {
+		v, err := got.GetOne(code)
+		if err != nil {
+			t.Fatalf("unexpected error for option %s: %v", code, err)
+		}
+
+		if len(v) != 0 {
+			t.Fatalf("unexpected value for zero-length option %s: %v", code, v)
+		}
+	}
+}
+
+// TestOptionsHex verifies that Options can be round-tripped through their
+// hexadecimal representation.
+func TestOptionsHex(t *testing.T) {
+	o := Options{
+		OptionServerID:    [][]byte{{0, 3, 0, 1, 1, 2, 3, 4, 5, 6}},
+		OptionClientID:    [][]byte{{0, 3, 0, 1, 6, 5, 4, 3, 2, 1}},
+		OptionRapidCommit: [][]byte{{}},
+		OptionUserClass:   [][]byte{{0, 1, 'a'}, {0, 1, 'b'}},
+	}
+
+	const want = "0001000a00030001060504030201" +
+		"0002000a00030001010203040506" +
+		"000e0000" +
+		"000f0003000161" +
+		"000f0003000162"
+
+	got, err := o.Hex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want != got {
+		t.Fatalf("unexpected Options hex:\n- want: %v\n-  got: %v", want, got)
+	}
+
+	parsed, err := ParseOptionsHex(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(o, parsed) {
+		t.Fatalf("unexpected Options:\n- want: %v\n-  got: %v", o, parsed)
+	}
+}
+
+// TestParseOptionsHexErrors verifies that ParseOptionsHex rejects invalid
+// hexadecimal strings and malformed options.
+func TestParseOptionsHexErrors(t *testing.T) {
+	if _, err := ParseOptionsHex("zz"); err == nil {
+		t.Fatal("expected an error for invalid hexadecimal, but none occurred")
+	}
+
+	if want, got := ErrInvalidOptions, func() error {
+		_, err := ParseOptionsHex("00010005ff")
+		return err
+	}(); want != got {
+		t.Fatalf("unexpected error for malformed options: %v != %v", want, got)
+	}
+}
+
+// TestOptionsMarshalBinaryTooLarge verifies that Options.MarshalBinary
+// rejects an option value whose length cannot be encoded.
+func TestOptionsMarshalBinaryTooLarge(t *testing.T) {
+	o := make(Options)
+	o.AddRaw(OptionUserClass, make([]byte, 65535))
+
+	if _, err := o.MarshalBinary(); err != nil {
+		t.Fatalf("unexpected error for maximum length option: %v", err)
+	}
+
+	o.AddRaw(OptionVendorClass, make([]byte, 65536))
+
+	if want, got := ErrOptionTooLarge, func() error {
+		_, err := o.MarshalBinary()
+		return err
+	}(); want != got {
+		t.Fatalf("unexpected error: %v != %v", want, got)
+	}
+
+	if want, got := ErrOptionTooLarge, func() error {
+		_, err := o.Hex()
+		return err
+	}(); want != got {
+		t.Fatalf("unexpected error for Options.Hex: %v != %v", want, got)
+	}
+}
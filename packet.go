@@ -1,6 +1,8 @@
 package dhcp6
 
 import (
+	"fmt"
+
 	"github.com/mdlayher/dhcp6/internal/buffer"
 )
 
@@ -24,6 +26,19 @@ type Packet struct {
 	Options Options
 }
 
+// SetTransactionID sets the transaction ID of a Packet from a byte slice.
+//
+// If the byte slice is not exactly 3 bytes long, ErrInvalidTransactionID is
+// returned and the Packet is not modified.
+func (p *Packet) SetTransactionID(b []byte) error {
+	if len(b) != len(p.TransactionID) {
+		return ErrInvalidTransactionID
+	}
+
+	copy(p.TransactionID[:], b)
+	return nil
+}
+
 // MarshalBinary allocates a byte slice containing the data
 // from a Packet.
 func (p *Packet) MarshalBinary() ([]byte, error) {
@@ -62,3 +77,109 @@ func (p *Packet) UnmarshalBinary(q []byte) error {
 	}
 	return nil
 }
+
+// optionRules specifies the options which a message of a given type must
+// include, and those it must not include.
+type optionRules struct {
+	required  []OptionCode
+	forbidden []OptionCode
+}
+
+// messageRules contains the option requirements for client and server
+// messages, as described in RFC 3315, Section 15.  Message types which are
+// not present have no requirements.
+var messageRules = map[MessageType]optionRules{
+	MessageTypeSolicit: {
+		required:  []OptionCode{OptionClientID},
+		forbidden: []OptionCode{OptionServerID},
+	},
+	MessageTypeAdvertise: {
+		required: []OptionCode{OptionClientID, OptionServerID},
+	},
+	MessageTypeRequest: {
+		required: []OptionCode{OptionClientID, OptionServerID},
+	},
+	MessageTypeConfirm: {
+		required:  []OptionCode{OptionClientID},
+		forbidden: []OptionCode{OptionServerID},
+	},
+	MessageTypeRenew: {
+		required: []OptionCode{OptionClientID, OptionServerID},
+	},
+	MessageTypeRebind: {
+		required:  []OptionCode{OptionClientID},
+		forbidden: []OptionCode{OptionServerID},
+	},
+	MessageTypeDecline: {
+		required: []OptionCode{OptionClientID, OptionServerID},
+	},
+	MessageTypeRelease: {
+		required: []OptionCode{OptionClientID, OptionServerID},
+	},
+	MessageTypeReply: {
+		required: []OptionCode{OptionServerID},
+	},
+	MessageTypeReconfigure: {
+		required: []OptionCode{OptionClientID, OptionServerID, OptionReconfMsg},
+	},
+}
+
+// Validate checks that a Packet includes the options required for its
+// message type, and does not include any options which are forbidden, as
+// described in RFC 3315, Section 15.  For example, a Solicit must include a
+// Client Identifier option, and must not include a Server Identifier option.
+//
+// Validate does not check the contents of any options.  Message types which
+// have no option requirements, such as Information-request, always pass
+// validation.  Packets are never validated automatically; a server may call
+// Validate to reject malformed requests uniformly.
+//
+// If a required option is missing, or a forbidden option is present, a
+// *ValidationError is returned.
+func (p *Packet) Validate() error {
+	rules := messageRules[p.MessageType]
+
+	for _, code := range rules.required {
+		if _, ok := p.Options[code]; !ok {
+			return &ValidationError{
+				MessageType: p.MessageType,
+				Option:      code,
+				Missing:     true,
+			}
+		}
+	}
+
+	for _, code := range rules.forbidden {
+		if _, ok := p.Options[code]; ok {
+			return &ValidationError{
+				MessageType: p.MessageType,
+				Option:      code,
+			}
+		}
+	}
+
+	return nil
+}
+
+// A ValidationError is returned by Packet.Validate when a Packet is missing
+// a required option, or includes a forbidden option.
+type ValidationError struct {
+	// MessageType specifies the message type of the invalid Packet.
+	MessageType MessageType
+
+	// Option specifies the option which is missing or forbidden.
+	Option OptionCode
+
+	// Missing reports whether Option is required but missing.  If false,
+	// Option is present but forbidden.
+	Missing bool
+}
+
+// Error implements error.
+func (e *ValidationError) Error() string {
+	if e.Missing {
+		return fmt.Sprintf("%s message must include option %s", e.MessageType, e.Option)
+	}
+
+	return fmt.Sprintf("%s message must not include option %s", e.MessageType, e.Option)
+}
@@ -141,3 +141,147 @@ func TestPacketUnmarshalBinary(t *testing.T) {
 		}
 	}
 }
+
+// TestPacketSetTransactionID verifies that Packet.SetTransactionID only
+// accepts transaction IDs which are exactly 3 bytes long.
+func TestPacketSetTransactionID(t *testing.T) {
+	var tests = []struct {
+		desc string
+		b    []byte
+		txID [3]byte
+		err  error
+	}{
+		{
+			desc: "nil transaction ID",
+			err:  ErrInvalidTransactionID,
+		},
+		{
+			desc: "short transaction ID",
+			b:    []byte{1, 2},
+			err:  ErrInvalidTransactionID,
+		},
+		{
+			desc: "long transaction ID",
+			b:    []byte{1, 2, 3, 4},
+			err:  ErrInvalidTransactionID,
+		},
+		{
+			desc: "OK",
+			b:    []byte{1, 2, 3},
+			txID: [3]byte{1, 2, 3},
+		},
+	}
+
+	for i, tt := range tests {
+		p := &Packet{
+			TransactionID: [3]byte{0xff, 0xff, 0xff},
+		}
+
+		if want, got := tt.err, p.SetTransactionID(tt.b); want != got {
+			t.Fatalf("[%02d] test %q, unexpected error: %v != %v",
+				i, tt.desc, want, got)
+		}
+		if tt.err != nil {
+			// Packet must not be modified on error
+			tt.txID = [3]byte{0xff, 0xff, 0xff}
+		}
+
+		if want, got := tt.txID, p.TransactionID; want != got {
+			t.Fatalf("[%02d] test %q, unexpected transaction ID: %v != %v",
+				i, tt.desc, want, got)
+		}
+	}
+}
+
+// TestPacketValidate verifies that Packet.Validate enforces the option
+// requirements for each message type.
+func TestPacketValidate(t *testing.T) {
+	id := []byte{0, 3, 0, 1, 1, 2, 3, 4, 5, 6}
+
+	var tests = []struct {
+		desc    string
+		mt      MessageType
+		options Options
+		err     error
+	}{
+		{
+			desc:    "Solicit with client ID",
+			mt:      MessageTypeSolicit,
+			options: Options{OptionClientID: [][]byte{id}},
+		},
+		{
+			desc: "Solicit missing client ID",
+			mt:   MessageTypeSolicit,
+			err: &ValidationError{
+				MessageType: MessageTypeSolicit,
+				Option:      OptionClientID,
+				Missing:     true,
+			},
+		},
+		{
+			desc: "Solicit with server ID",
+			mt:   MessageTypeSolicit,
+			options: Options{
+				OptionClientID: [][]byte{id},
+				OptionServerID: [][]byte{id},
+			},
+			err: &ValidationError{
+				MessageType: MessageTypeSolicit,
+				Option:      OptionServerID,
+			},
+		},
+		{
+			desc:    "Request missing server ID",
+			mt:      MessageTypeRequest,
+			options: Options{OptionClientID: [][]byte{id}},
+			err: &ValidationError{
+				MessageType: MessageTypeRequest,
+				Option:      OptionServerID,
+				Missing:     true,
+			},
+		},
+		{
+			desc:    "Reply with server ID",
+			mt:      MessageTypeReply,
+			options: Options{OptionServerID: [][]byte{id}},
+		},
+		{
+			desc: "Reply missing server ID",
+			mt:   MessageTypeReply,
+			err: &ValidationError{
+				MessageType: MessageTypeReply,
+				Option:      OptionServerID,
+				Missing:     true,
+			},
+		},
+		{
+			desc: "Information-request with no options",
+			mt:   MessageTypeInformationRequest,
+		},
+	}
+
+	for i, tt := range tests {
+		p := &Packet{
+			MessageType: tt.mt,
+			Options:     tt.options,
+		}
+
+		if want, got := tt.err, p.Validate(); !reflect.DeepEqual(want, got) {
+			t.Fatalf("[%02d] test %q, unexpected error: %v != %v",
+				i, tt.desc, want, got)
+		}
+	}
+}
+
+// TestValidationErrorError verifies that a ValidationError names the message
+// type and option which failed validation.
+func TestValidationErrorError(t *testing.T) {
+	err := &ValidationError{
+		MessageType: MessageTypeSolicit,
+		Option:      OptionServerID,
+	}
+
+	if want, got := "MessageTypeSolicit message must not include option OptionServerID", err.Error(); want != got {
+		t.Fatalf("unexpected error string: %q != %q", want, got)
+	}
+}
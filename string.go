@@ -4,16 +4,27 @@ package dhcp6
 
 import "fmt"
 
-const _MessageType_name = "MessageTypeSolicitMessageTypeAdvertiseMessageTypeRequestMessageTypeConfirmMessageTypeRenewMessageTypeRebindMessageTypeReplyMessageTypeReleaseMessageTypeDeclineMessageTypeReconfigureMessageTypeInformationRequestMessageTypeRelayForwMessageTypeRelayReplMessageTypeLeasequeryMessageTypeLeasequeryReplyMessageTypeLeasequeryDoneMessageTypeLeasequeryDataMessageTypeReconfigureRequestMessageTypeReconfigureReplyMessageTypeDHCPv4QueryMessageTypeDHCPv4Response"
+const (
+	_MessageType_name_0 = "MessageTypeSolicitMessageTypeAdvertiseMessageTypeRequestMessageTypeConfirmMessageTypeRenewMessageTypeRebindMessageTypeReplyMessageTypeReleaseMessageTypeDeclineMessageTypeReconfigureMessageTypeInformationRequestMessageTypeRelayForwMessageTypeRelayReplMessageTypeLeasequeryMessageTypeLeasequeryReplyMessageTypeLeasequeryDoneMessageTypeLeasequeryDataMessageTypeReconfigureRequestMessageTypeReconfigureReplyMessageTypeDHCPv4QueryMessageTypeDHCPv4Response"
+	_MessageType_name_1 = "MessageTypeAddrRegInformMessageTypeAddrRegReply"
+)
 
-var _MessageType_index = [...]uint16{0, 18, 38, 56, 74, 90, 107, 123, 141, 159, 181, 210, 230, 250, 271, 297, 322, 347, 376, 403, 425, 450}
+var (
+	_MessageType_index_0 = [...]uint16{0, 18, 38, 56, 74, 90, 107, 123, 141, 159, 181, 210, 230, 250, 271, 297, 322, 347, 376, 403, 425, 450}
+	_MessageType_index_1 = [...]uint8{0, 24, 47}
+)
 
 func (i MessageType) String() string {
-	i -= 1
-	if i >= MessageType(len(_MessageType_index)-1) {
-		return fmt.Sprintf("MessageType(%d)", i+1)
+	switch {
+	case 1 <= i && i <= 21:
+		i -= 1
+		return _MessageType_name_0[_MessageType_index_0[i]:_MessageType_index_0[i+1]]
+	case 36 <= i && i <= 37:
+		i -= 36
+		return _MessageType_name_1[_MessageType_index_1[i]:_MessageType_index_1[i+1]]
+	default:
+		return fmt.Sprintf("MessageType(%d)", i)
 	}
-	return _MessageType_name[_MessageType_index[i]:_MessageType_index[i+1]]
 }
 
 const _Status_name = "StatusSuccessStatusUnspecFailStatusNoAddrsAvailStatusNoBindingStatusNotOnLinkStatusUseMulticastStatusNoPrefixAvailStatusUnknownQueryTypeStatusMalformedQueryStatusNotConfiguredStatusNotAllowedStatusQueryTerminated"
@@ -30,17 +41,25 @@ func (i Status) String() string {
 const (
 	_OptionCode_name_0 = "OptionClientIDOptionServerIDOptionIANAOptionIATAOptionIAAddrOptionOROOptionPreferenceOptionElapsedTimeOptionRelayMsg"
 	_OptionCode_name_1 = "OptionAuthOptionUnicastOptionStatusCodeOptionRapidCommitOptionUserClassOptionVendorClassOptionVendorOptsOptionInterfaceIDOptionReconfMsgOptionReconfAccept"
-	_OptionCode_name_2 = "OptionIAPDOptionIAPrefix"
-	_OptionCode_name_3 = "OptionRemoteIdentifier"
-	_OptionCode_name_4 = "OptionBootFileURLOptionBootFileParamOptionClientArchTypeOptionNII"
+	_OptionCode_name_2 = "OptionDNSServersOptionDomainListOptionIAPDOptionIAPrefix"
+	_OptionCode_name_3 = "OptionSNTPServersOptionInformationRefreshTime"
+	_OptionCode_name_4 = "OptionRemoteIdentifier"
+	_OptionCode_name_5 = "OptionNTPServer"
+	_OptionCode_name_6 = "OptionBootFileURLOptionBootFileParamOptionClientArchTypeOptionNII"
+	_OptionCode_name_7 = "OptionRSOO"
+	_OptionCode_name_8 = "OptionAddrRegEnable"
 )
 
 var (
 	_OptionCode_index_0 = [...]uint8{0, 14, 28, 38, 48, 60, 69, 85, 102, 116}
 	_OptionCode_index_1 = [...]uint8{0, 10, 23, 39, 56, 71, 88, 104, 121, 136, 154}
-	_OptionCode_index_2 = [...]uint8{0, 10, 24}
-	_OptionCode_index_3 = [...]uint8{0, 22}
-	_OptionCode_index_4 = [...]uint8{0, 17, 36, 56, 65}
+	_OptionCode_index_2 = [...]uint8{0, 16, 32, 42, 56}
+	_OptionCode_index_3 = [...]uint8{0, 17, 45}
+	_OptionCode_index_4 = [...]uint8{0, 22}
+	_OptionCode_index_5 = [...]uint8{0, 15}
+	_OptionCode_index_6 = [...]uint8{0, 17, 36, 56, 65}
+	_OptionCode_index_7 = [...]uint8{0, 10}
+	_OptionCode_index_8 = [...]uint8{0, 19}
 )
 
 func (i OptionCode) String() string {
@@ -51,14 +70,23 @@ func (i OptionCode) String() string {
 	case 11 <= i && i <= 20:
 		i -= 11
 		return _OptionCode_name_1[_OptionCode_index_1[i]:_OptionCode_index_1[i+1]]
-	case 25 <= i && i <= 26:
-		i -= 25
+	case 23 <= i && i <= 26:
+		i -= 23
 		return _OptionCode_name_2[_OptionCode_index_2[i]:_OptionCode_index_2[i+1]]
+	case 31 <= i && i <= 32:
+		i -= 31
+		return _OptionCode_name_3[_OptionCode_index_3[i]:_OptionCode_index_3[i+1]]
 	case i == 37:
-		return _OptionCode_name_3
+		return _OptionCode_name_4
+	case i == 56:
+		return _OptionCode_name_5
 	case 59 <= i && i <= 62:
 		i -= 59
-		return _OptionCode_name_4[_OptionCode_index_4[i]:_OptionCode_index_4[i+1]]
+		return _OptionCode_name_6[_OptionCode_index_6[i]:_OptionCode_index_6[i+1]]
+	case i == 66:
+		return _OptionCode_name_7
+	case i == 148:
+		return _OptionCode_name_8
 	default:
 		return fmt.Sprintf("OptionCode(%d)", i)
 	}
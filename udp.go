@@ -0,0 +1,136 @@
+package dhcp6
+
+import (
+	"encoding/binary"
+	"math"
+	"net"
+
+	"github.com/mdlayher/dhcp6/internal/buffer"
+)
+
+const (
+	// ipv6HeaderLen is the length of a fixed IPv6 header, as described in
+	// RFC 8200, Section 3.
+	ipv6HeaderLen = 40
+
+	// udpHeaderLen is the length of a UDP header, as described in RFC 768.
+	udpHeaderLen = 8
+
+	// protocolUDP is the IPv6 next header value which indicates UDP.
+	protocolUDP = 17
+)
+
+// MarshalUDP6 allocates a byte slice containing a complete IPv6 and UDP
+// frame which encapsulates payload, such as a marshaled Packet.  The UDP
+// checksum is computed over the IPv6 pseudo-header, as described in
+// RFC 8200, Section 8.1.
+//
+// MarshalUDP6 is useful when sending or injecting DHCP messages using raw
+// sockets, such as when testing relay agents.  Servers using the normal
+// socket path do not need it.
+//
+// If src or dst do not contain a valid IPv6 address and port,
+// ErrInvalidUDP6Addr is returned.  If payload is too large to fit in a
+// single UDP datagram, ErrPayloadTooLarge is returned.
+func MarshalUDP6(src, dst *net.UDPAddr, hopLimit uint8, payload []byte) ([]byte, error) {
+	if !validUDP6Addr(src) || !validUDP6Addr(dst) {
+		return nil, ErrInvalidUDP6Addr
+	}
+
+	udpLen := udpHeaderLen + len(payload)
+	if udpLen > math.MaxUint16 {
+		return nil, ErrPayloadTooLarge
+	}
+
+	b := buffer.New(nil)
+
+	// 4 bytes: version 6, traffic class 0, flow label 0
+	// 2 bytes: payload length
+	// 1 byte: next header
+	// 1 byte: hop limit
+	// 16 bytes: source address
+	// 16 bytes: destination address
+	b.Write32(6 << 28)
+	b.Write16(uint16(udpLen))
+	b.Write8(protocolUDP)
+	b.Write8(hopLimit)
+	b.WriteBytes(src.IP.To16())
+	b.WriteBytes(dst.IP.To16())
+
+	// 2 bytes: source port
+	// 2 bytes: destination port
+	// 2 bytes: length
+	// 2 bytes: checksum, filled in once the datagram is complete
+	b.Write16(uint16(src.Port))
+	b.Write16(uint16(dst.Port))
+	b.Write16(uint16(udpLen))
+	b.Write16(0)
+	b.WriteBytes(payload)
+
+	frame := b.Data()
+	udp := frame[ipv6HeaderLen:]
+	binary.BigEndian.PutUint16(udp[6:8], UDP6Checksum(src.IP, dst.IP, udp))
+
+	return frame, nil
+}
+
+// UDP6Checksum computes the checksum of a UDP datagram sent from src to dst
+// over IPv6, including the IPv6 pseudo-header, as described in RFC 8200,
+// Section 8.1.  The checksum field of udp must be zero.
+//
+// Because a checksum of zero is not permitted for UDP over IPv6, a computed
+// checksum of zero is returned as 0xffff.
+func UDP6Checksum(src, dst net.IP, udp []byte) uint16 {
+	var sum uint32
+
+	// Pseudo-header: source address, destination address, upper-layer
+	// packet length, and next header.
+	sum = checksumAdd(sum, src.To16())
+	sum = checksumAdd(sum, dst.To16())
+	sum += uint32(len(udp)) >> 16
+	sum += uint32(len(udp)) & 0xffff
+	sum += protocolUDP
+
+	sum = checksumAdd(sum, udp)
+
+	// Fold carries into the low 16 bits.
+	for sum > 0xffff {
+		sum = (sum >> 16) + (sum & 0xffff)
+	}
+
+	c := ^uint16(sum)
+	if c == 0 {
+		return 0xffff
+	}
+
+	return c
+}
+
+// checksumAdd adds the 16-bit words of b to sum, padding b with a zero byte
+// if its length is odd.
+func checksumAdd(sum uint32, b []byte) uint32 {
+	for len(b) >= 2 {
+		sum += uint32(binary.BigEndian.Uint16(b))
+		b = b[2:]
+	}
+	if len(b) == 1 {
+		sum += uint32(b[0]) << 8
+	}
+
+	// Fold carries early to avoid overflow on large inputs.
+	for sum > 0xffff {
+		sum = (sum >> 16) + (sum & 0xffff)
+	}
+
+	return sum
+}
+
+// validUDP6Addr reports whether addr contains an IPv6 address and a valid
+// UDP port.
+func validUDP6Addr(addr *net.UDPAddr) bool {
+	if addr == nil || addr.Port < 0 || addr.Port > math.MaxUint16 {
+		return false
+	}
+
+	return addr.IP.To16() != nil && addr.IP.To4() == nil
+}
@@ -0,0 +1,145 @@
+package dhcp6
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"strings"
+	"testing"
+)
+
+// TestUDP6Checksum verifies that UDP6Checksum computes correct checksums
+// over known payloads.
+func TestUDP6Checksum(t *testing.T) {
+	var tests = []struct {
+		desc     string
+		src      net.IP
+		dst      net.IP
+		sport    uint16
+		dport    uint16
+		payload  []byte
+		checksum uint16
+	}{
+		{
+			desc:     "empty payload",
+			src:      net.ParseIP("2001:db8::1"),
+			dst:      net.ParseIP("2001:db8::2"),
+			sport:    547,
+			dport:    546,
+			checksum: 0xa024,
+		},
+		{
+			desc:     "Solicit with Rapid Commit",
+			src:      net.ParseIP("fe80::1"),
+			dst:      net.ParseIP("ff02::1:2"),
+			sport:    546,
+			dport:    547,
+			payload:  []byte{1, 0, 1, 2, 0, 14, 0, 0},
+			checksum: 0xfbf1,
+		},
+		{
+			desc:     "odd length payload",
+			src:      net.ParseIP("fe80::1"),
+			dst:      net.ParseIP("ff02::1:2"),
+			sport:    546,
+			dport:    547,
+			payload:  []byte{1, 0, 1, 2, 0, 14, 0, 0, 0xff},
+			checksum: 0xfcee,
+		},
+	}
+
+	for i, tt := range tests {
+		udp := make([]byte, 8+len(tt.payload))
+		binary.BigEndian.PutUint16(udp[0:2], tt.sport)
+		binary.BigEndian.PutUint16(udp[2:4], tt.dport)
+		binary.BigEndian.PutUint16(udp[4:6], uint16(len(udp)))
+		copy(udp[8:], tt.payload)
+
+		if want, got := tt.checksum, UDP6Checksum(tt.src, tt.dst, udp); want != got {
+			t.Fatalf("[%02d] test %q, unexpected checksum: %#04x != %#04x",
+				i, tt.desc, want, got)
+		}
+	}
+}
+
+// TestMarshalUDP6 verifies that MarshalUDP6 builds a complete IPv6 and UDP
+// frame around a payload.
+func TestMarshalUDP6(t *testing.T) {
+	src := &net.UDPAddr{IP: net.ParseIP("fe80::1"), Port: 546}
+	dst := &net.UDPAddr{IP: net.ParseIP("ff02::1:2"), Port: 547}
+	payload := []byte{1, 0, 1, 2, 0, 14, 0, 0}
+
+	b, err := MarshalUDP6(src, dst, 1, payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []byte{
+		// IPv6 header
+		0x60, 0, 0, 0,
+		0, 16,
+		17,
+		1,
+		0xfe, 0x80, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1,
+		0xff, 0x02, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 0, 2,
+
+		// UDP header
+		0x02, 0x22,
+		0x02, 0x23,
+		0, 16,
+		0xfb, 0xf1,
+
+		// Payload
+		1, 0, 1, 2, 0, 14, 0, 0,
+	}
+
+	if got := b; !bytes.Equal(want, got) {
+		t.Fatalf("unexpected frame:\n- want: %v\n-  got: %v", want, got)
+	}
+}
+
+// TestMarshalUDP6Errors verifies that MarshalUDP6 rejects invalid addresses
+// and oversized payloads.
+func TestMarshalUDP6Errors(t *testing.T) {
+	ip6 := &net.UDPAddr{IP: net.ParseIP("fe80::1"), Port: 546}
+
+	var tests = []struct {
+		desc    string
+		src     *net.UDPAddr
+		dst     *net.UDPAddr
+		payload []byte
+		err     error
+	}{
+		{
+			desc: "nil source address",
+			dst:  ip6,
+			err:  ErrInvalidUDP6Addr,
+		},
+		{
+			desc: "IPv4 destination address",
+			src:  ip6,
+			dst:  &net.UDPAddr{IP: net.IPv4(192, 0, 2, 1), Port: 547},
+			err:  ErrInvalidUDP6Addr,
+		},
+		{
+			desc: "port out of range",
+			src:  ip6,
+			dst:  &net.UDPAddr{IP: net.ParseIP("ff02::1:2"), Port: 65536},
+			err:  ErrInvalidUDP6Addr,
+		},
+		{
+			desc:    "payload too large",
+			src:     ip6,
+			dst:     ip6,
+			payload: []byte(strings.Repeat("a", 65535-7)),
+			err:     ErrPayloadTooLarge,
+		},
+	}
+
+	for i, tt := range tests {
+		if _, err := MarshalUDP6(tt.src, tt.dst, 1, tt.payload); err != tt.err {
+			t.Fatalf("[%02d] test %q, unexpected error: %v != %v",
+				i, tt.desc, tt.err, err)
+		}
+	}
+}